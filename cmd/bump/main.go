@@ -0,0 +1,124 @@
+// Command bump computes the next version of a properties file entry, like main.version in version.ini,
+// from a bump level or from conventional commit messages, and writes it back.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/wizards-0/go-pins/logger"
+	"github.com/wizards-0/go-pins/props"
+	"github.com/wizards-0/go-pins/semver"
+)
+
+const (
+	EXIT_OK         = 0
+	EXIT_USAGE      = 1
+	EXIT_ERROR      = 2
+	EXIT_NO_RELEASE = 3
+)
+
+const usage = `Usage: bump [flags]
+
+Prints the next version and writes it to the properties file, e.g.
+  bump -level minor
+  git log --format=%B v0.1.33..HEAD | bump -commits -
+
+With -commits, the level is computed from conventional commit messages:
+breaking changes bump major, feat bumps minor, fix & perf bump patch.
+Exits with 3, if commits do not need a release and -fallback is none.
+
+Flags:
+`
+
+func main() {
+	os.Exit(run(os.Args, os.Stdin, os.Stdout, os.Stderr))
+}
+
+func run(osArgs []string, stdin io.Reader, out io.Writer, errOut io.Writer) int {
+	fs := flag.NewFlagSet(osArgs[0], flag.ContinueOnError)
+	fs.SetOutput(errOut)
+	fs.Usage = func() {
+		fmt.Fprint(errOut, usage)
+		fs.PrintDefaults()
+	}
+	file := fs.String("file", "version.ini", "properties file with the version")
+	key := fs.String("key", "main.version", "property key of the version")
+	levelName := fs.String("level", "", "bump level, one of major | minor | patch | prerelease. Required without -commits")
+	commits := fs.String("commits", "", "file with conventional commit messages, or '-' for stdin")
+	fallbackName := fs.String("fallback", "none", "bump level, when commits do not need a release")
+	preid := fs.String("preid", "", "pre-release identifier for prerelease level, e.g. rc")
+	dryRun := fs.Bool("dry-run", false, "print the next version without writing it")
+	if err := fs.Parse(osArgs[1:]); err != nil {
+		return EXIT_USAGE
+	}
+	if fs.NArg() > 0 || (*levelName == "") == (*commits == "") {
+		fmt.Fprintln(errOut, "exactly one of -level or -commits is required")
+		fs.Usage()
+		return EXIT_USAGE
+	}
+
+	level, err := getLevel(*levelName, *commits, *fallbackName, stdin)
+	if err != nil {
+		logger.Error(err)
+		return EXIT_USAGE
+	}
+	if level == semver.BUMP_NONE {
+		fmt.Fprintln(errOut, "no release needed")
+		return EXIT_NO_RELEASE
+	}
+
+	p, err := props.ReadFiles(*file)
+	if err != nil {
+		logger.Error(err)
+		return EXIT_ERROR
+	}
+	current, exists := p[*key]
+	if !exists {
+		logger.Error(fmt.Sprintf("missing key %v in file %v", *key, *file))
+		return EXIT_ERROR
+	}
+	v, err := semver.Parse(current)
+	if err != nil {
+		logger.Error(fmt.Errorf("error in parsing %v from file %v\n%w", *key, *file, err))
+		return EXIT_ERROR
+	}
+	next := v.Bump(level, *preid).String()
+	if strings.HasPrefix(current, "v") {
+		next = "v" + next
+	}
+	if !*dryRun {
+		if err := props.UpdateFile(*file, map[string]string{*key: next}); err != nil {
+			logger.Error(err)
+			return EXIT_ERROR
+		}
+	}
+	fmt.Fprintln(out, next)
+	return EXIT_OK
+}
+
+func getLevel(levelName string, commits string, fallbackName string, stdin io.Reader) (semver.BumpLevel, error) {
+	if levelName != "" {
+		return semver.ParseBumpLevel(levelName)
+	}
+	fallback, err := semver.ParseBumpLevel(fallbackName)
+	if err != nil {
+		return semver.BUMP_NONE, err
+	}
+	var messages []byte
+	if commits == "-" {
+		messages, err = io.ReadAll(stdin)
+	} else {
+		messages, err = os.ReadFile(commits)
+	}
+	if err != nil {
+		return semver.BUMP_NONE, fmt.Errorf("error in reading commit messages from %v\n%w", commits, err)
+	}
+	if level := semver.BumpLevelFromCommits(string(messages)); level != semver.BUMP_NONE {
+		return level, nil
+	}
+	return fallback, nil
+}
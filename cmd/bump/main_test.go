@@ -0,0 +1,94 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wizards-0/go-pins/logger"
+)
+
+var buf = bytes.Buffer{}
+
+func setup(t *testing.T, content string) string {
+	logger.SetWriter(&buf, &buf, &buf, &buf)
+	versionFile := filepath.Join(t.TempDir(), "version.ini")
+	os.WriteFile(versionFile, []byte(content), 0644)
+	return versionFile
+}
+
+func readFile(path string) string {
+	b, _ := os.ReadFile(path)
+	return string(b)
+}
+
+func TestBumpLevel(t *testing.T) {
+	assert := assert.New(t)
+	file := setup(t, "main.version=v0.1.33")
+	out := bytes.Buffer{}
+
+	assert.Equal(EXIT_OK, run([]string{"bump", "-file", file, "-level", "patch"}, nil, &out, &buf))
+	assert.Equal("v0.1.34\n", out.String())
+	assert.Equal("main.version=v0.1.34", readFile(file))
+
+	out.Reset()
+	assert.Equal(EXIT_OK, run([]string{"bump", "-file", file, "-level", "prerelease", "-preid", "rc"}, nil, &out, &buf))
+	assert.Equal("v0.1.35-rc.1\n", out.String())
+
+	out.Reset()
+	assert.Equal(EXIT_OK, run([]string{"bump", "-file", file, "-level", "minor", "-dry-run"}, nil, &out, &buf))
+	assert.Equal("v0.2.0\n", out.String())
+	assert.Equal("main.version=v0.1.35-rc.1", readFile(file))
+}
+
+func TestBumpFromCommits(t *testing.T) {
+	assert := assert.New(t)
+	file := setup(t, "# release version\napp.version=1.4.2\n")
+	out := bytes.Buffer{}
+
+	stdin := strings.NewReader("feat(api): add endpoint\n\nfix: typo\n")
+	assert.Equal(EXIT_OK, run([]string{"bump", "-file", file, "-key", "app.version", "-commits", "-"}, stdin, &out, &buf))
+	assert.Equal("1.5.0\n", out.String())
+	assert.Equal("# release version\napp.version=1.5.0\n", readFile(file))
+
+	commitsFile := filepath.Join(t.TempDir(), "commits.txt")
+	os.WriteFile(commitsFile, []byte("refactor!: drop v1 api\n"), 0644)
+	out.Reset()
+	assert.Equal(EXIT_OK, run([]string{"bump", "-file", file, "-key", "app.version", "-commits", commitsFile}, nil, &out, &buf))
+	assert.Equal("2.0.0\n", out.String())
+
+	// Commits without release, use fallback level or exit with no release
+	out.Reset()
+	stdin = strings.NewReader("docs: update readme\n")
+	assert.Equal(EXIT_OK, run([]string{"bump", "-file", file, "-key", "app.version", "-commits", "-", "-fallback", "patch"}, stdin, &out, &buf))
+	assert.Equal("2.0.1\n", out.String())
+	stdin = strings.NewReader("chore: cleanup\n")
+	assert.Equal(EXIT_NO_RELEASE, run([]string{"bump", "-file", file, "-key", "app.version", "-commits", "-"}, stdin, &out, &buf))
+	assert.Equal("# release version\napp.version=2.0.1\n", readFile(file))
+}
+
+func TestUsageErrors(t *testing.T) {
+	assert := assert.New(t)
+	file := setup(t, "main.version=0.1.0\n")
+
+	assert.Equal(EXIT_USAGE, run([]string{"bump", "-bad-flag"}, nil, &buf, &buf))
+	assert.Equal(EXIT_USAGE, run([]string{"bump", "-file", file}, nil, &buf, &buf))
+	assert.Equal(EXIT_USAGE, run([]string{"bump", "-file", file, "-level", "patch", "-commits", "-"}, nil, &buf, &buf))
+	assert.Equal(EXIT_USAGE, run([]string{"bump", "-file", file, "-level", "patch", "extra"}, nil, &buf, &buf))
+	assert.Equal(EXIT_USAGE, run([]string{"bump", "-file", file, "-level", "huge"}, nil, &buf, &buf))
+	assert.Equal(EXIT_USAGE, run([]string{"bump", "-file", file, "-commits", "-", "-fallback", "huge"}, strings.NewReader(""), &buf, &buf))
+	assert.Equal(EXIT_USAGE, run([]string{"bump", "-file", file, "-commits", "../invalid-path"}, nil, &buf, &buf))
+}
+
+func TestVersionErrors(t *testing.T) {
+	assert := assert.New(t)
+	file := setup(t, "main.version=1.2\n")
+
+	assert.Equal(EXIT_ERROR, run([]string{"bump", "-file", "../invalid-path", "-level", "patch"}, nil, &buf, &buf))
+	assert.Equal(EXIT_ERROR, run([]string{"bump", "-file", file, "-key", "missing.version", "-level", "patch"}, nil, &buf, &buf))
+	assert.Equal(EXIT_ERROR, run([]string{"bump", "-file", file, "-level", "patch"}, nil, &buf, &buf))
+	assert.Equal("main.version=1.2\n", readFile(file))
+}
@@ -0,0 +1,6 @@
+//go:build mysql
+
+package main
+
+// Registers driver mysql, when built with -tags mysql
+import _ "github.com/go-sql-driver/mysql"
@@ -0,0 +1,6 @@
+//go:build postgres
+
+package main
+
+// Registers driver postgres, when built with -tags postgres
+import _ "github.com/lib/pq"
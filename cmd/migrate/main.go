@@ -0,0 +1,153 @@
+package main
+
+import (
+	"database/sql"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/wizards-0/go-pins/logger"
+	"github.com/wizards-0/go-pins/migrator"
+	"github.com/wizards-0/go-pins/props"
+)
+
+const (
+	EXIT_OK         = 0
+	EXIT_USAGE      = 1
+	EXIT_VALIDATION = 2
+	EXIT_DRIFT      = 3
+	EXIT_EXECUTION  = 4
+)
+
+const usage = `Usage: migrate [flags] <command> [args]
+
+Commands are passed on to the migrator, e.g.
+  migrate -dsn app.db run ./migrations
+  migrate -dsn app.db rollback 1-2
+  migrate -dsn prod.db export migration-log.json
+  migrate -dsn staging.db import migration-log.json
+
+Each setting is read from the flag, else from the environment variable, else from the properties file.
+
+Driver sqlite3 is always available. Drivers postgres & mysql are added with build tags, e.g.
+  go build -tags postgres,mysql ./cmd/migrate
+Other drivers need a main package, which imports the driver and runs migrator.New(db, schema).Cli(os.Args).
+
+Flags:
+`
+
+type config struct {
+	driver string
+	dsn    string
+	schema string
+	dir    string
+}
+
+func main() {
+	os.Exit(run(os.Args, os.Getenv, os.Stderr))
+}
+
+func run(osArgs []string, getenv func(string) string, out io.Writer) int {
+	fs := flag.NewFlagSet(osArgs[0], flag.ContinueOnError)
+	fs.SetOutput(out)
+	fs.Usage = func() {
+		fmt.Fprint(out, usage)
+		fs.PrintDefaults()
+	}
+	cfgFile := fs.String("config", "", "properties file with migrate.* keys. Env MIGRATE_CONFIG")
+	driver := fs.String("driver", "", "database driver, one of "+strings.Join(sql.Drivers(), " | ")+". Env MIGRATE_DRIVER, property migrate.driver. Default sqlite3")
+	dsn := fs.String("dsn", "", "data source name. Env MIGRATE_DSN, property migrate.dsn")
+	schema := fs.String("schema", "", "schema for the migration tables. Env MIGRATE_SCHEMA, property migrate.schema")
+	dir := fs.String("dir", "", "migrations directory, used by run when no path is given. Env MIGRATE_DIR, property migrate.dir")
+	if err := fs.Parse(osArgs[1:]); err != nil {
+		return EXIT_USAGE
+	}
+
+	cfg, cfgErr := loadConfig(
+		config{driver: *driver, dsn: *dsn, schema: *schema, dir: *dir},
+		firstNonEmpty(*cfgFile, getenv("MIGRATE_CONFIG")),
+		getenv,
+	)
+	if cfgErr != nil {
+		logger.Error(cfgErr)
+		return EXIT_USAGE
+	}
+
+	cmdArgs := fs.Args()
+	if len(cmdArgs) == 0 {
+		fs.Usage()
+		return EXIT_USAGE
+	}
+	if cmdArgs[0] == "run" && len(cmdArgs) == 1 && cfg.dir != "" {
+		cmdArgs = append(cmdArgs, cfg.dir)
+	}
+	if cfg.dsn == "" {
+		logger.Error("missing data source name. Set it with -dsn, MIGRATE_DSN or migrate.dsn")
+		return EXIT_USAGE
+	}
+	if !slices.Contains(sql.Drivers(), cfg.driver) {
+		logger.Error(fmt.Sprintf("unknown driver %v, available drivers are %v. Drivers postgres & mysql are added by building with -tags postgres,mysql",
+			cfg.driver, strings.Join(sql.Drivers(), ", ")))
+		return EXIT_USAGE
+	}
+
+	db, dbErr := sqlx.Connect(cfg.driver, cfg.dsn)
+	if dbErr != nil {
+		logger.Error(fmt.Errorf("error in connecting to database with driver %v\n%w", cfg.driver, dbErr))
+		return EXIT_EXECUTION
+	}
+	defer db.Close()
+
+	err := migrator.New(db, cfg.schema).Cli(append([]string{osArgs[0]}, cmdArgs...))
+	if err != nil {
+		logger.Error(err)
+	}
+	return exitCode(err)
+}
+
+func loadConfig(flags config, cfgFile string, getenv func(string) string) (config, error) {
+	fileProps := map[string]string{}
+	if cfgFile != "" {
+		p, err := props.ReadFiles(cfgFile)
+		if err != nil {
+			return config{}, fmt.Errorf("error in reading config file %v\n%w", cfgFile, err)
+		}
+		fileProps = p
+	}
+	return config{
+		driver: firstNonEmpty(flags.driver, getenv("MIGRATE_DRIVER"), fileProps["migrate.driver"], "sqlite3"),
+		dsn:    firstNonEmpty(flags.dsn, getenv("MIGRATE_DSN"), fileProps["migrate.dsn"]),
+		schema: firstNonEmpty(flags.schema, getenv("MIGRATE_SCHEMA"), fileProps["migrate.schema"]),
+		dir:    firstNonEmpty(flags.dir, getenv("MIGRATE_DIR"), fileProps["migrate.dir"]),
+	}, nil
+}
+
+func exitCode(err error) int {
+	switch {
+	case err == nil:
+		return EXIT_OK
+	case errors.Is(err, migrator.ErrValidation):
+		return EXIT_VALIDATION
+	case errors.Is(err, migrator.ErrDrift):
+		return EXIT_DRIFT
+	case errors.Is(err, migrator.ErrExecution):
+		return EXIT_EXECUTION
+	default:
+		return EXIT_USAGE
+	}
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wizards-0/go-pins/logger"
+)
+
+var buf = bytes.Buffer{}
+
+func setup(t *testing.T) (string, string) {
+	logger.SetWriter(&buf, &buf, &buf, &buf)
+	tmpDir := t.TempDir()
+	migrationsDir := filepath.Join(tmpDir, "migrations")
+	os.Mkdir(migrationsDir, 0755)
+	writeMigration(migrationsDir, "1", "user-setup", "CREATE TABLE USERS(ID INT);", "DROP TABLE USERS;")
+	return "file:" + filepath.Join(tmpDir, "test.db"), migrationsDir
+}
+
+func writeMigration(dir string, ver string, name string, query string, rollback string) {
+	os.WriteFile(filepath.Join(dir, ver+"."+name+".query.sql"), []byte(query), 0644)
+	os.WriteFile(filepath.Join(dir, ver+"."+name+".rollback.sql"), []byte(rollback), 0644)
+}
+
+func noEnv(string) string {
+	return ""
+}
+
+func TestRunAndRollback(t *testing.T) {
+	assert := assert.New(t)
+	dsn, dir := setup(t)
+
+	assert.Equal(EXIT_OK, run([]string{"migrate", "-dsn", dsn, "run", dir}, noEnv, &buf))
+	assert.Equal(EXIT_OK, run([]string{"migrate", "-dsn", dsn, "rollback", "0"}, noEnv, &buf))
+}
+
+func TestConfigFromEnvAndProperties(t *testing.T) {
+	assert := assert.New(t)
+	dsn, dir := setup(t)
+	env := map[string]string{"MIGRATE_DSN": dsn, "MIGRATE_DIR": dir}
+	assert.Equal(EXIT_OK, run([]string{"migrate", "run"}, func(k string) string { return env[k] }, &buf))
+
+	propsFile := filepath.Join(t.TempDir(), "migrate.properties")
+	os.WriteFile(propsFile, []byte("migrate.driver=sqlite3\nmigrate.dsn="+dsn+"\nmigrate.dir="+dir+"\n"), 0644)
+	assert.Equal(EXIT_OK, run([]string{"migrate", "-config", propsFile, "run"}, noEnv, &buf))
+
+	cfg, err := loadConfig(config{schema: "flag_schema"}, propsFile, func(k string) string {
+		if k == "MIGRATE_DIR" {
+			return "env-dir"
+		}
+		return ""
+	})
+	assert.Nil(err)
+	assert.Equal(config{driver: "sqlite3", dsn: dsn, schema: "flag_schema", dir: "env-dir"}, cfg)
+
+	_, err = loadConfig(config{}, "../invalid-path", noEnv)
+	assert.ErrorContains(err, "error in reading config file")
+}
+
+func TestUsageErrors(t *testing.T) {
+	assert := assert.New(t)
+	dsn, _ := setup(t)
+
+	assert.Equal(EXIT_USAGE, run([]string{"migrate", "-bad-flag"}, noEnv, &buf))
+	assert.Equal(EXIT_USAGE, run([]string{"migrate", "-dsn", dsn}, noEnv, &buf))
+	assert.Equal(EXIT_USAGE, run([]string{"migrate", "run", "some-dir"}, noEnv, &buf))
+	assert.Equal(EXIT_USAGE, run([]string{"migrate", "-dsn", dsn, "bad-cmd"}, noEnv, &buf))
+	assert.Equal(EXIT_USAGE, run([]string{"migrate", "-config", "../invalid-path", "run"}, noEnv, &buf))
+
+	buf.Reset()
+	assert.Equal(EXIT_USAGE, run([]string{"migrate", "-driver", "no-driver", "-dsn", dsn, "run"}, noEnv, &buf))
+	assert.Contains(buf.String(), "unknown driver no-driver, available drivers are sqlite3")
+}
+
+func TestFailureExitCodes(t *testing.T) {
+	assert := assert.New(t)
+	dsn, dir := setup(t)
+
+	assert.Equal(EXIT_VALIDATION, run([]string{"migrate", "-dsn", dsn, "run", "../../resources/test/migrations/missing-rollback"}, noEnv, &buf))
+
+	assert.Equal(EXIT_OK, run([]string{"migrate", "-dsn", dsn, "run", dir}, noEnv, &buf))
+	writeMigration(dir, "1", "user-setup", "CREATE TABLE USERS(ID INT, NAME TEXT);", "DROP TABLE USERS;")
+	assert.Equal(EXIT_DRIFT, run([]string{"migrate", "-dsn", dsn, "run", dir}, noEnv, &buf))
+
+	writeMigration(dir, "1", "user-setup", "CREATE TABLE USERS(ID INT);", "DROP TABLE USERS;")
+	writeMigration(dir, "2", "bad-query", "CREATE TABLE USERS(ID INT);", "SELECT 1;")
+	assert.Equal(EXIT_EXECUTION, run([]string{"migrate", "-dsn", dsn, "run", dir}, noEnv, &buf))
+}
@@ -0,0 +1,141 @@
+package admin
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/wizards-0/go-pins/ginu"
+	"github.com/wizards-0/go-pins/logger"
+	"github.com/wizards-0/go-pins/migrator"
+	"github.com/wizards-0/go-pins/migrator/types"
+	"github.com/wizards-0/go-pins/webutil"
+)
+
+type Config struct {
+	// Directory with migration files, used by status, plan and run endpoints
+	MigrationsDir string
+	// Registers run and rollback endpoints
+	EnableWrite bool
+	// Guards run and rollback endpoints, requests are rejected with 403 if it returns false. Required with EnableWrite
+	Authorize func(r *http.Request) bool
+}
+
+var errMissingDir = errors.New("migrations directory is not configured for migration admin endpoints")
+
+// Registers following endpoints on the router. E.g. RegisterGinRoutes(engine.Group("/admin/migrations"), m, cfg)
+//
+//	GET  /logs
+//	GET  /status
+//	GET  /plan
+//	POST /run                (EnableWrite)
+//	POST /rollback/:version  (EnableWrite)
+func RegisterGinRoutes(r gin.IRouter, m migrator.Migrator, cfg Config) {
+	validateConfig(cfg)
+	r.GET("/logs", func(ctx *gin.Context) {
+		mLogs, err := m.GetMigrationLogs()
+		ginu.SendResponse(ctx, mLogs, err)
+	})
+	r.GET("/status", func(ctx *gin.Context) {
+		status, err := getStatus(m, cfg)
+		ginu.SendResponse(ctx, status, err)
+	})
+	r.GET("/plan", func(ctx *gin.Context) {
+		status, err := getStatus(m, cfg)
+		ginu.SendResponse(ctx, status.Pending, err)
+	})
+	if !cfg.EnableWrite {
+		return
+	}
+	w := r.Group("", func(ctx *gin.Context) {
+		if !cfg.Authorize(ctx.Request) {
+			ctx.AbortWithStatus(http.StatusForbidden)
+		}
+	})
+	w.POST("/run", func(ctx *gin.Context) {
+		mLogs, err := run(m, cfg)
+		ginu.SendResponse(ctx, mLogs, err)
+	})
+	w.POST("/rollback/:version", func(ctx *gin.Context) {
+		mLogs, err := rollback(m, cfg, ctx.Param("version"))
+		ginu.SendResponse(ctx, mLogs, err)
+	})
+}
+
+// Registers the same endpoints as RegisterGinRoutes under prefix. E.g. RegisterMuxRoutes(mux, "/admin/migrations", m, cfg)
+func RegisterMuxRoutes(mux *http.ServeMux, prefix string, m migrator.Migrator, cfg Config) {
+	validateConfig(cfg)
+	webutil.RegisterParamsHandler(mux, "GET "+prefix+"/logs", func(q map[string]string, p map[string]string) webutil.HttpResponse {
+		mLogs, err := m.GetMigrationLogs()
+		return webutil.HttpResponse{Body: mLogs, Error: err}
+	})
+	webutil.RegisterParamsHandler(mux, "GET "+prefix+"/status", func(q map[string]string, p map[string]string) webutil.HttpResponse {
+		status, err := getStatus(m, cfg)
+		return webutil.HttpResponse{Body: status, Error: err}
+	})
+	webutil.RegisterParamsHandler(mux, "GET "+prefix+"/plan", func(q map[string]string, p map[string]string) webutil.HttpResponse {
+		status, err := getStatus(m, cfg)
+		return webutil.HttpResponse{Body: status.Pending, Error: err}
+	})
+	if !cfg.EnableWrite {
+		return
+	}
+	// Write endpoints are registered on their own mux, so that the guard can wrap them
+	writeMux := http.NewServeMux()
+	webutil.RegisterParamsHandler(writeMux, "POST "+prefix+"/run", func(q map[string]string, p map[string]string) webutil.HttpResponse {
+		mLogs, err := run(m, cfg)
+		return webutil.HttpResponse{Body: mLogs, Error: err}
+	})
+	webutil.RegisterParamsHandler(writeMux, "POST "+prefix+"/rollback/{version}", func(q map[string]string, p map[string]string) webutil.HttpResponse {
+		mLogs, err := rollback(m, cfg, p["version"])
+		return webutil.HttpResponse{Body: mLogs, Error: err}
+	}, "version")
+	guarded := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !cfg.Authorize(r) {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		writeMux.ServeHTTP(w, r)
+	})
+	mux.Handle("POST "+prefix+"/run", guarded)
+	mux.Handle("POST "+prefix+"/rollback/{version}", guarded)
+}
+
+func validateConfig(cfg Config) {
+	if cfg.EnableWrite && cfg.Authorize == nil {
+		msg := "Authorize is required, when write endpoints are enabled for migration admin"
+		logger.Error(msg)
+		panic(msg)
+	}
+}
+
+func getStatus(m migrator.Migrator, cfg Config) (types.MigrationStatus, error) {
+	if cfg.MigrationsDir == "" {
+		return types.MigrationStatus{}, errMissingDir
+	}
+	return m.GetStatus(cfg.MigrationsDir)
+}
+
+func run(m migrator.Migrator, cfg Config) ([]types.MigrationLog, error) {
+	if cfg.MigrationsDir == "" {
+		return nil, errMissingDir
+	}
+	if err := m.RunMigrationsFromDirectory(cfg.MigrationsDir); err != nil {
+		return nil, err
+	}
+	return m.GetMigrationLogs()
+}
+
+// Rollback scripts are checked against the migrations directory, if it is configured
+func rollback(m migrator.Migrator, cfg Config, version string) ([]types.MigrationLog, error) {
+	var err error
+	if cfg.MigrationsDir != "" {
+		err = m.RollbackFromDirectory(cfg.MigrationsDir, version)
+	} else {
+		err = m.Rollback(version)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return m.GetMigrationLogs()
+}
@@ -0,0 +1,147 @@
+package admin
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/wizards-0/go-pins/logger"
+	"github.com/wizards-0/go-pins/migrator"
+	"github.com/wizards-0/go-pins/migrator/types"
+)
+
+const VALID_PATH = "../../resources/test/migrations/valid"
+const PREFIX = "/admin/migrations"
+
+var log = bytes.Buffer{}
+
+var cfg = Config{
+	MigrationsDir: VALID_PATH,
+	EnableWrite:   true,
+	Authorize: func(r *http.Request) bool {
+		return r.Header.Get("X-Admin-Token") == "secret"
+	},
+}
+
+func setup() (*sqlx.DB, migrator.Migrator) {
+	logger.SetWriter(&log, &log, &log, &log)
+	gin.SetMode(gin.TestMode)
+	db, err := sqlx.Open("sqlite3", "file:admin-test-db?mode=memory&cache=shared")
+	if err != nil {
+		panic(err)
+	}
+	m := migrator.New(db, "")
+	m.Migrate([]types.Migration{})
+	return db, m
+}
+
+func TestGinRoutes(t *testing.T) {
+	db, m := setup()
+	defer db.Close()
+	srv := gin.New()
+	RegisterGinRoutes(srv.Group(PREFIX), m, cfg)
+	testRoutes(t, srv)
+}
+
+func TestMuxRoutes(t *testing.T) {
+	db, m := setup()
+	defer db.Close()
+	mux := http.NewServeMux()
+	RegisterMuxRoutes(mux, PREFIX, m, cfg)
+	testRoutes(t, mux)
+}
+
+func testRoutes(t *testing.T, h http.Handler) {
+	assert := assert.New(t)
+
+	status := types.MigrationStatus{}
+	code := serve(h, "GET", "/status", "", &status)
+	assert.Equal(http.StatusOK, code)
+	assert.Equal(0, len(status.Applied))
+	assert.Equal(1, len(status.Pending))
+
+	plan := []types.Migration{}
+	serve(h, "GET", "/plan", "", &plan)
+	assert.Equal("user-setup", plan[0].Name)
+
+	assert.Equal(http.StatusForbidden, serve(h, "POST", "/run", "", nil))
+	assert.Equal(http.StatusForbidden, serve(h, "POST", "/rollback/0", "bad-token", nil))
+
+	mLogs := []types.MigrationLog{}
+	code = serve(h, "POST", "/run", "secret", &mLogs)
+	assert.Equal(http.StatusOK, code)
+	assert.Equal(1, len(mLogs))
+	assert.Equal("1", mLogs[0].Version)
+
+	mLogs = []types.MigrationLog{}
+	serve(h, "GET", "/logs", "", &mLogs)
+	assert.Equal(1, len(mLogs))
+
+	serve(h, "GET", "/status", "", &status)
+	assert.Equal(1, len(status.Applied))
+	assert.Equal(0, len(status.Pending))
+
+	code = serve(h, "POST", "/rollback/0", "secret", &mLogs)
+	assert.Equal(http.StatusOK, code)
+	assert.Equal(0, len(mLogs))
+}
+
+func TestReadOnlyRoutes(t *testing.T) {
+	assert := assert.New(t)
+	db, m := setup()
+	defer db.Close()
+
+	srv := gin.New()
+	RegisterGinRoutes(srv.Group(PREFIX), m, Config{})
+	mux := http.NewServeMux()
+	RegisterMuxRoutes(mux, PREFIX, m, Config{})
+
+	for _, h := range []http.Handler{srv, mux} {
+		assert.Equal(http.StatusNotFound, serve(h, "POST", "/run", "secret", nil))
+		assert.Equal(http.StatusInternalServerError, serve(h, "GET", "/status", "", nil))
+		assert.Equal(http.StatusInternalServerError, serve(h, "GET", "/plan", "", nil))
+		assert.Equal(http.StatusOK, serve(h, "GET", "/logs", "", nil))
+	}
+
+	_, err := run(m, Config{})
+	assert.ErrorContains(err, "migrations directory is not configured")
+	_, err = run(m, Config{MigrationsDir: "../invalid-path"})
+	assert.ErrorContains(err, "error while running migrations from path")
+	_, err = rollback(m, Config{}, "0")
+	assert.Nil(err)
+	_, err = rollback(m, Config{MigrationsDir: "../invalid-path"}, "0")
+	assert.ErrorContains(err, "error while running rollback from path")
+	db.Close()
+	_, err = rollback(m, Config{}, "0")
+	assert.ErrorContains(err, "error in executing rollback")
+}
+
+func TestMissingAuthorize(t *testing.T) {
+	assert := assert.New(t)
+	db, m := setup()
+	defer db.Close()
+	assert.Panics(func() {
+		RegisterGinRoutes(gin.New(), m, Config{EnableWrite: true})
+	})
+	assert.Panics(func() {
+		RegisterMuxRoutes(http.NewServeMux(), PREFIX, m, Config{EnableWrite: true})
+	})
+}
+
+func serve(h http.Handler, method string, path string, token string, respBody any) int {
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(method, PREFIX+path, nil)
+	if token != "" {
+		req.Header.Set("X-Admin-Token", token)
+	}
+	h.ServeHTTP(w, req)
+	if respBody != nil {
+		json.Unmarshal(w.Body.Bytes(), respBody)
+	}
+	return w.Code
+}
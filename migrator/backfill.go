@@ -0,0 +1,140 @@
+package migrator
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/wizards-0/go-pins/logger"
+	"github.com/wizards-0/go-pins/migrator/types"
+	"github.com/wizards-0/go-pins/pins"
+)
+
+const DEFAULT_BACKFILL_BATCH_SIZE = 1000
+
+var identifierPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*(\.[A-Za-z_][A-Za-z0-9_]*)?$`)
+
+// Data migration, executed in key range batches, each in its own transaction.
+// Query receives the exclusive lower and inclusive upper key of the batch as args, e.g.
+// "UPDATE users SET status = 'active' WHERE status IS NULL AND id > ? AND id <= ?"
+type Backfill struct {
+	// Identifies the checkpoint of the backfill, re-running a backfill with the same name resumes it
+	Name string
+	// Table and integer key column, which are used for splitting rows into batches
+	Table     string
+	KeyColumn string
+	Query     string
+	// Number of keys per batch. Defaults to DEFAULT_BACKFILL_BATCH_SIZE
+	BatchSize int
+}
+
+// Runs the backfill from its last checkpoint. Checkpoint is saved in the same transaction as each batch,
+// so a failed backfill resumes after the last committed batch
+func (m *migrator) Backfill(b Backfill) error {
+	if err := validateBackfill(b); err != nil {
+		return withKind(ErrValidation, err)
+	}
+	batchSize := b.BatchSize
+	if batchSize <= 0 {
+		batchSize = DEFAULT_BACKFILL_BATCH_SIZE
+	}
+	var setupErr error
+	txErr := m.txProvider.WithTx(func(tx *sqlx.Tx) bool {
+		setupErr = m.dao.SetupMigrationTable(tx)
+		return setupErr == nil
+	})
+	if err := pins.MergeErrors(txErr, setupErr); err != nil {
+		return withKind(ErrExecution, fmt.Errorf("error while running backfill '%v'\n%w", b.Name, err))
+	}
+
+	checkpoint, err := m.getBackfillCheckpoint(b.Name)
+	if err != nil {
+		return withKind(ErrExecution, err)
+	}
+	if checkpoint.Date != 0 {
+		logger.Info(fmt.Sprintf("Resuming backfill '%v' after key %v, %v rows processed so far", b.Name, checkpoint.LastKey, checkpoint.Rows))
+	}
+	for {
+		done := false
+		err := m.withRetry(fmt.Sprintf("batch of backfill '%v' after key %v", b.Name, checkpoint.LastKey), func() error {
+			var batchErr error
+			done, checkpoint, batchErr = m.executeBatch(b, checkpoint, batchSize)
+			return batchErr
+		})
+		if err != nil {
+			return withKind(ErrExecution, fmt.Errorf("error while running backfill '%v'\n%w", b.Name, err))
+		}
+		if done {
+			logger.Info(fmt.Sprintf("Backfill '%v' completed, %v rows processed", b.Name, checkpoint.Rows))
+			return nil
+		}
+		logger.Debug(fmt.Sprintf("Backfill '%v' processed keys up to %v, %v rows so far", b.Name, checkpoint.LastKey, checkpoint.Rows))
+	}
+}
+
+func validateBackfill(b Backfill) error {
+	errs := []error{}
+	if b.Name == "" {
+		errs = append(errs, errors.New("backfill name is required"))
+	}
+	if !identifierPattern.MatchString(b.Table) {
+		errs = append(errs, fmt.Errorf("invalid backfill table '%v'", b.Table))
+	}
+	if !identifierPattern.MatchString(b.KeyColumn) {
+		errs = append(errs, fmt.Errorf("invalid backfill key column '%v'", b.KeyColumn))
+	}
+	if b.Query == "" {
+		errs = append(errs, errors.New("backfill query is required"))
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("invalid backfill '%v'\n%w", b.Name, errors.Join(errs...))
+	}
+	return nil
+}
+
+// Returns a checkpoint before the first key, if backfill has not been started yet
+func (m *migrator) getBackfillCheckpoint(name string) (checkpoint types.BackfillCheckpoint, err error) {
+	txErr := m.txProvider.WithTx(func(tx *sqlx.Tx) bool {
+		var saved *types.BackfillCheckpoint
+		saved, err = m.dao.GetBackfillCheckpoint(tx, name)
+		if saved != nil {
+			checkpoint = *saved
+		} else {
+			checkpoint = types.BackfillCheckpoint{Name: name, LastKey: math.MinInt64}
+		}
+		return err == nil
+	})
+	return checkpoint, pins.MergeErrors(txErr, err)
+}
+
+// Executes the next batch and saves its checkpoint. Returns true, if there are no keys left
+func (m *migrator) executeBatch(b Backfill, checkpoint types.BackfillCheckpoint, batchSize int) (done bool, next types.BackfillCheckpoint, err error) {
+	next = checkpoint
+	txErr := m.txProvider.WithTx(func(tx *sqlx.Tx) bool {
+		end, endErr := m.dao.GetBatchEnd(tx, b.Table, b.KeyColumn, checkpoint.LastKey, batchSize)
+		if endErr != nil || end == nil {
+			done, err = end == nil, endErr
+			return false
+		}
+		rows, execErr := m.dao.ExecuteBatch(tx, b.Query, checkpoint.LastKey, *end)
+		if execErr != nil {
+			err = execErr
+			return false
+		}
+		next = types.BackfillCheckpoint{
+			Name:    checkpoint.Name,
+			LastKey: *end,
+			Rows:    checkpoint.Rows + rows,
+			Date:    time.Now().UnixMilli(),
+		}
+		err = m.dao.SaveBackfillCheckpoint(tx, next)
+		return err == nil
+	})
+	if err != nil || txErr != nil {
+		return false, checkpoint, pins.MergeErrors(txErr, err)
+	}
+	return done, next, nil
+}
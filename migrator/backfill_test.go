@@ -0,0 +1,116 @@
+package migrator
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	mocks "github.com/wizards-0/go-pins/mocks/migrator/dao"
+)
+
+func setupBackfillTable(rows int) {
+	db.MustExec("CREATE TABLE USERS(ID INTEGER PRIMARY KEY, STATUS VARCHAR(20))")
+	for i := 1; i <= rows; i++ {
+		// Gaps in keys should not matter
+		db.MustExec("INSERT INTO USERS(ID) VALUES (?)", i*3)
+	}
+}
+
+var usersBackfill = Backfill{
+	Name:      "activate-users",
+	Table:     "USERS",
+	KeyColumn: "ID",
+	Query:     "UPDATE USERS SET STATUS = 'active' WHERE STATUS IS NULL AND ID > ? AND ID <= ?",
+	BatchSize: 4,
+}
+
+func TestBackfill(t *testing.T) {
+	assert := assert.New(t)
+	setup()
+	defer tearDown()
+	setupBackfillTable(10)
+
+	buf.Reset()
+	err := mRun.Backfill(usersBackfill)
+	assert.Nil(err)
+	var pending int
+	assert.Nil(db.Get(&pending, "SELECT COUNT(*) FROM USERS WHERE STATUS IS NULL"))
+	assert.Equal(0, pending)
+	assert.Contains(buf.String(), "Backfill 'activate-users' processed keys up to 12, 4 rows so far")
+	assert.Contains(buf.String(), "Backfill 'activate-users' completed, 10 rows processed")
+
+	checkpoint, err := mRun.(*migrator).getBackfillCheckpoint(usersBackfill.Name)
+	assert.Nil(err)
+	assert.Equal(int64(30), checkpoint.LastKey)
+	assert.Equal(int64(10), checkpoint.Rows)
+
+	// Completed backfill only processes rows added after the last key
+	db.MustExec("INSERT INTO USERS(ID) VALUES (1), (31)")
+	assert.Nil(mRun.Backfill(usersBackfill))
+	assert.Nil(db.Get(&pending, "SELECT COUNT(*) FROM USERS WHERE STATUS IS NULL"))
+	assert.Equal(1, pending)
+}
+
+func TestBackfillResume(t *testing.T) {
+	assert := assert.New(t)
+	setup()
+	defer tearDown()
+	setupBackfillTable(10)
+	mockDao := mocks.NewMockMigrationDao(mDao, t)
+	mRun = newMigrator(db, mockDao)
+
+	// First batch succeeds, second one fails
+	mockDao.PassThrough("SetupMigrationTable", "GetBackfillCheckpoint", "GetBatchEnd", "ExecuteBatch", "SaveBackfillCheckpoint", "GetBatchEnd")
+	mockDao.EXPECT().ExecuteBatch(TYPE_TX, mock.Anything, mock.Anything, mock.Anything).Return(0, errors.New("connection reset")).Once()
+	err := mRun.Backfill(usersBackfill)
+	assert.ErrorContains(err, "connection reset")
+	assert.ErrorIs(err, ErrExecution)
+	var done int
+	assert.Nil(db.Get(&done, "SELECT COUNT(*) FROM USERS WHERE STATUS = 'active'"))
+	assert.Equal(4, done)
+
+	mRun = New(db, "")
+	buf.Reset()
+	assert.Nil(mRun.Backfill(usersBackfill))
+	assert.Contains(buf.String(), "Resuming backfill 'activate-users' after key 12, 4 rows processed so far")
+	assert.Contains(buf.String(), "Backfill 'activate-users' completed, 10 rows processed")
+	assert.Nil(db.Get(&done, "SELECT COUNT(*) FROM USERS WHERE STATUS = 'active'"))
+	assert.Equal(10, done)
+}
+
+func TestBackfillEmptyTable(t *testing.T) {
+	assert := assert.New(t)
+	setup()
+	defer tearDown()
+	setupBackfillTable(0)
+	assert.Nil(mRun.Backfill(usersBackfill))
+	checkpoint, err := mRun.(*migrator).getBackfillCheckpoint(usersBackfill.Name)
+	assert.Nil(err)
+	assert.Equal(int64(0), checkpoint.Date)
+}
+
+func TestBackfillValidation(t *testing.T) {
+	assert := assert.New(t)
+	setup()
+	defer tearDown()
+	err := mRun.Backfill(Backfill{Table: "USERS; DROP TABLE USERS", KeyColumn: "ID"})
+	assert.ErrorIs(err, ErrValidation)
+	assert.ErrorContains(err, "backfill name is required")
+	assert.ErrorContains(err, "invalid backfill table 'USERS; DROP TABLE USERS'")
+	assert.ErrorContains(err, "backfill query is required")
+	assert.NotContains(err.Error(), "invalid backfill key column")
+}
+
+func TestBackfillCheckpointRollback(t *testing.T) {
+	assert := assert.New(t)
+	setup()
+	defer tearDown()
+	setupBackfillTable(3)
+	b := usersBackfill
+	b.Query = "UPDATE USERS SET MISSING_COLUMN = 1 WHERE ID > ? AND ID <= ?"
+	assert.NotNil(mRun.Backfill(b))
+	checkpoint, err := mRun.(*migrator).getBackfillCheckpoint(b.Name)
+	assert.Nil(err)
+	assert.Equal(int64(0), checkpoint.Date)
+}
@@ -0,0 +1,148 @@
+package migrator
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/samber/lo"
+	"github.com/wizards-0/go-pins/logger"
+	"github.com/wizards-0/go-pins/migrator/types"
+	"github.com/wizards-0/go-pins/pins"
+)
+
+func (m *migrator) parseBaselineArgs(args []string) error {
+	if len(args) != 3 {
+		return errors.New("baseline command needs to have version as second arg, and path as third arg. Example 'baseline 1.1 ./migrations'")
+	}
+	return m.Baseline(args[2], args[1])
+}
+
+func (m *migrator) parseRepairArgs(args []string) error {
+	if len(args) != 2 {
+		return errors.New("repair command needs to have path as second arg. Example 'repair ./migrations'")
+	}
+	return m.Repair(args[1])
+}
+
+// Records migrations in the directory with version <= ver as applied, without executing them,
+// e.g. when adopting the migrator for a database, whose schema was created by other means.
+// Versions already applied are left unchanged, and fail the baseline if their query differs from the directory
+func (m *migrator) Baseline(path string, ver string) error {
+	mArr, err := parseDirectoryWithOptions(path, m.parserOptions, m.comparator)
+	if err != nil {
+		return withKind(ErrValidation, fmt.Errorf("error while running baseline from path %v\n%w", path, err))
+	}
+	if !slices.ContainsFunc(mArr, func(mig types.Migration) bool { return mig.Version == ver }) {
+		return withKind(ErrValidation, fmt.Errorf("error while running baseline, version %v is not found in path %v", ver, path))
+	}
+	mArr = lo.Filter(mArr, func(mig types.Migration, _ int) bool {
+		return m.comparator.Compare(mig.Version, ver) <= 0
+	})
+
+	var baselineErr error
+	count := 0
+	txErr := m.txProvider.WithTx(func(tx *sqlx.Tx) bool {
+		count, baselineErr = m.baseline(tx, mArr)
+		return baselineErr == nil
+	})
+	if err := pins.MergeErrors(txErr, baselineErr); err != nil {
+		if errors.Is(err, ErrDrift) {
+			return fmt.Errorf("error while running baseline\n%w", err)
+		}
+		return withKind(ErrExecution, fmt.Errorf("error while running baseline\n%w", err))
+	}
+	logger.Info(fmt.Sprintf("Baselined %v migrations up to version %v, %v were already applied", count, ver, len(mArr)-count))
+	return nil
+}
+
+// Returns the number of migrations recorded as applied
+func (m *migrator) baseline(tx *sqlx.Tx, mArr []types.Migration) (int, error) {
+	if err := m.dao.SetupMigrationTable(tx); err != nil {
+		return 0, err
+	}
+	mLogs, err := m.dao.GetMigrationLogs(tx)
+	if err != nil {
+		return 0, err
+	}
+	mMap := lo.KeyBy(mLogs, func(mLog types.MigrationLog) string {
+		return mLog.Version
+	})
+	maxId := lo.Max(lo.Map(mLogs, func(mLog types.MigrationLog, _ int) int {
+		return mLog.Id
+	}))
+	count := 0
+	for _, mig := range mArr {
+		hash := hashQuery(mig.Query)
+		if mLog, exists := mMap[mig.Version]; exists {
+			if hashErr := validateHash(mLog, hash); hashErr != nil {
+				return 0, withKind(ErrDrift, fmt.Errorf("error while validating hash for '%v-%v'\n%w", mLog.Version, mLog.Name, hashErr))
+			}
+			continue
+		}
+		maxId++
+		mLog, insertErr := m.insertMigrationLog(tx, mig, maxId, hash)
+		if insertErr != nil {
+			return 0, fmt.Errorf("error while recording baseline of '%v-%v'\n%w", mig.Version, mig.Name, insertErr)
+		}
+		if err := m.insertMigrationHistory(tx, mLog, types.HISTORY_ACTION_BASELINE); err != nil {
+			return 0, err
+		}
+		count++
+	}
+	return count, nil
+}
+
+// Updates name, query & rollback of applied migrations to the ones in the directory, without executing them,
+// e.g. after an intended edit of an applied migration, which would otherwise fail Migrate with a checksum error.
+// Applied versions, which are not in the directory, are left unchanged
+func (m *migrator) Repair(path string) error {
+	mArr, err := parseDirectoryWithOptions(path, m.parserOptions, m.comparator)
+	if err != nil {
+		return withKind(ErrValidation, fmt.Errorf("error while running repair from path %v\n%w", path, err))
+	}
+	var repairErr error
+	count := 0
+	txErr := m.txProvider.WithTx(func(tx *sqlx.Tx) bool {
+		count, repairErr = m.repair(tx, mArr)
+		return repairErr == nil
+	})
+	if err := pins.MergeErrors(txErr, repairErr); err != nil {
+		return withKind(ErrExecution, fmt.Errorf("error while running repair\n%w", err))
+	}
+	logger.Info(fmt.Sprintf("Repaired %v migration logs", count))
+	return nil
+}
+
+// Returns the number of migration logs updated
+func (m *migrator) repair(tx *sqlx.Tx, mArr []types.Migration) (int, error) {
+	mLogs, err := m.dao.GetMigrationLogs(tx)
+	if err != nil {
+		return 0, err
+	}
+	slices.SortStableFunc(mLogs, func(l1, l2 types.MigrationLog) int {
+		return m.comparator.Compare(l1.Version, l2.Version)
+	})
+	mMap := lo.KeyBy(mArr, func(mig types.Migration) string {
+		return mig.Version
+	})
+	count := 0
+	for _, mLog := range mLogs {
+		mig, exists := mMap[mLog.Version]
+		hash := hashQuery(mig.Query)
+		if !exists || (mLog.Migration == mig && mLog.Hash == hash) {
+			continue
+		}
+		mLog.Migration = mig
+		mLog.Hash = hash
+		if err := m.dao.UpdateMigrationLog(tx, mLog); err != nil {
+			return 0, fmt.Errorf("error while repairing migration log of '%v-%v'\n%w", mLog.Version, mLog.Name, err)
+		}
+		if err := m.insertMigrationHistory(tx, mLog, types.HISTORY_ACTION_REPAIR); err != nil {
+			return 0, err
+		}
+		count++
+	}
+	return count, nil
+}
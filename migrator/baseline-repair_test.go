@@ -0,0 +1,126 @@
+package migrator
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+	"github.com/wizards-0/go-pins/migrator/types"
+	mocks "github.com/wizards-0/go-pins/mocks/migrator/dao"
+)
+
+func writeMigrationFiles(t *testing.T, dir string, ver string, name string, query string, rollback string) {
+	if err := os.WriteFile(dir+ver+"."+name+".query.sql", []byte(query), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(dir+ver+"."+name+".rollback.sql", []byte(rollback), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func countTables(names ...string) int {
+	var count int
+	db.Get(&count, "SELECT COUNT(*) FROM sqlite_master WHERE name IN ('"+strings.Join(names, "', '")+"')")
+	return count
+}
+
+func TestBaseline(t *testing.T) {
+	assert := assert.New(t)
+	setup()
+	defer tearDown()
+	dir := t.TempDir() + "/"
+	writeMigrationFiles(t, dir, "1", "a", "CREATE TABLE BASE_A(ID INT);", "DROP TABLE BASE_A;")
+	writeMigrationFiles(t, dir, "2", "b", "CREATE TABLE BASE_B(ID INT);", "DROP TABLE BASE_B;")
+	writeMigrationFiles(t, dir, "3", "c", "CREATE TABLE BASE_C(ID INT);", "DROP TABLE BASE_C;")
+
+	assert.Nil(mRun.Cli([]string{"main", "baseline", "2", dir}))
+	mLogs, _ := mRun.GetMigrationLogs()
+	assert.Equal([]string{"1", "2"}, lo.Map(mLogs, func(mLog types.MigrationLog, _ int) string { return mLog.Version }))
+	history, _ := mRun.GetMigrationHistory("1")
+	assert.Equal(types.HISTORY_ACTION_BASELINE, history[0].Action)
+	assert.Equal(0, countTables("BASE_A", "BASE_B"))
+
+	// Baselined migrations are not executed by later runs
+	assert.Nil(mRun.RunMigrationsFromDirectory(dir))
+	assert.Equal(0, countTables("BASE_A", "BASE_B"))
+	assert.Equal(1, countTables("BASE_C"))
+
+	buf.Reset()
+	assert.Nil(mRun.Baseline(dir, "3"))
+	assert.Contains(buf.String(), "Baselined 0 migrations up to version 3, 3 were already applied")
+
+	writeMigrationFiles(t, dir, "1", "a", "CREATE TABLE BASE_A(ID BIGINT);", "DROP TABLE BASE_A;")
+	err := mRun.Baseline(dir, "3")
+	assert.ErrorIs(err, ErrDrift)
+	assert.ErrorContains(err, "error while validating hash for '1-a'")
+
+	err = mRun.Baseline(dir, "9")
+	assert.ErrorIs(err, ErrValidation)
+	assert.ErrorContains(err, "version 9 is not found in path")
+	assert.ErrorIs(mRun.Baseline("../invalid-path", "1"), ErrValidation)
+	assert.ErrorContains(mRun.Cli([]string{"main", "baseline", "1"}), "baseline command needs to have version as second arg")
+}
+
+func TestRepair(t *testing.T) {
+	assert := assert.New(t)
+	setup()
+	defer tearDown()
+	dir := t.TempDir() + "/"
+	writeMigrationFiles(t, dir, "1", "a", "CREATE TABLE REPAIR_A(ID INT);", "DROP TABLE REPAIR_A;")
+	writeMigrationFiles(t, dir, "2", "b", "CREATE TABLE REPAIR_B(ID INT);", "DROP TABLE REPAIR_B;")
+	assert.Nil(mRun.RunMigrationsFromDirectory(dir))
+
+	writeMigrationFiles(t, dir, "1", "a", "-- Table for a\nCREATE TABLE REPAIR_A(ID INT);", "DROP TABLE IF EXISTS REPAIR_A;")
+	assert.ErrorIs(mRun.RunMigrationsFromDirectory(dir), ErrDrift)
+
+	// Applied versions missing in the directory are left unchanged
+	assert.Nil(os.Remove(dir + "2.b.query.sql"))
+	assert.Nil(os.Remove(dir + "2.b.rollback.sql"))
+	buf.Reset()
+	assert.Nil(mRun.Cli([]string{"main", "repair", dir}))
+	assert.Contains(buf.String(), "Repaired 1 migration logs")
+	mLogs, _ := mRun.GetMigrationLogs()
+	assert.Equal("-- Table for a\nCREATE TABLE REPAIR_A(ID INT);", mLogs[0].Query)
+	assert.Equal("DROP TABLE IF EXISTS REPAIR_A;", mLogs[0].Rollback)
+	assert.Equal("CREATE TABLE REPAIR_B(ID INT);", mLogs[1].Query)
+	history, _ := mRun.GetMigrationHistory("1")
+	assert.Equal(2, len(history))
+	assert.Equal(types.HISTORY_ACTION_REPAIR, history[1].Action)
+	assert.Equal(mLogs[0].Hash, history[1].Hash)
+	assert.Nil(mRun.RunMigrationsFromDirectory(dir))
+
+	buf.Reset()
+	assert.Nil(mRun.Repair(dir))
+	assert.Contains(buf.String(), "Repaired 0 migration logs")
+	assert.ErrorIs(mRun.Repair("../invalid-path"), ErrValidation)
+	assert.ErrorContains(mRun.Cli([]string{"main", "repair"}), "repair command needs to have path as second arg")
+}
+
+func TestBaselineRepairErrors(t *testing.T) {
+	assert := assert.New(t)
+	setup()
+	defer tearDown()
+	dir := t.TempDir() + "/"
+	writeMigrationFiles(t, dir, "1", "a", "CREATE TABLE ERR_A(ID INT);", "DROP TABLE ERR_A;")
+	assert.Nil(mRun.RunMigrationsFromDirectory(dir))
+	writeMigrationFiles(t, dir, "1", "a", "CREATE TABLE ERR_A(ID BIGINT);", "DROP TABLE ERR_A;")
+	mockDao := mocks.NewMockMigrationDao(mDao, t)
+	mRun = newMigrator(db, mockDao)
+
+	mockDao.PassThrough("SetupMigrationTable")
+	mockDao.EXPECT().GetMigrationLogs(TYPE_TX).Return(nil, errors.New("fetch error")).Twice()
+	err := mRun.Baseline(dir, "1")
+	assert.ErrorIs(err, ErrExecution)
+	assert.ErrorContains(err, "fetch error")
+	err = mRun.Repair(dir)
+	assert.ErrorIs(err, ErrExecution)
+	assert.ErrorContains(err, "fetch error")
+
+	mockDao.PassThrough("GetMigrationLogs")
+	mockDao.EXPECT().UpdateMigrationLog(TYPE_TX, TYPE_MIGRATION_LOG).Return(errors.New("update error"))
+	err = mRun.Repair(dir)
+	assert.ErrorContains(err, "error while repairing migration log of '1-a'\nupdate error")
+}
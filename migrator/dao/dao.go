@@ -1,52 +1,60 @@
 package dao
 
 import (
+	"database/sql"
+	"errors"
 	"fmt"
-	"sort"
+	"strings"
 
 	"github.com/jmoiron/sqlx"
 	_ "github.com/mattn/go-sqlite3"
 	"github.com/wizards-0/go-pins/logger"
 	"github.com/wizards-0/go-pins/migrator/types"
-	"github.com/wizards-0/go-pins/semver"
 )
 
 type MigrationDao interface {
 	GetMigrationLogs(tx *sqlx.Tx) ([]types.MigrationLog, error)
 	InsertMigrationLog(tx *sqlx.Tx, mLog types.MigrationLog) error
 	DeleteMigrationLog(tx *sqlx.Tx, mLog types.MigrationLog) error
+	UpdateMigrationLog(tx *sqlx.Tx, mLog types.MigrationLog) error
+	GetMigrationHistory(tx *sqlx.Tx, version string) ([]types.MigrationHistory, error)
+	InsertMigrationHistory(tx *sqlx.Tx, h types.MigrationHistory) error
 	ExecuteQuery(tx *sqlx.Tx, m types.Migration) error
 	ExecuteRollback(tx *sqlx.Tx, m types.Migration) error
+	CheckPrecondition(tx *sqlx.Tx, query string) (bool, error)
+	GetBackfillCheckpoint(tx *sqlx.Tx, name string) (*types.BackfillCheckpoint, error)
+	SaveBackfillCheckpoint(tx *sqlx.Tx, c types.BackfillCheckpoint) error
+	GetBatchEnd(tx *sqlx.Tx, table string, keyColumn string, after int64, size int) (*int64, error)
+	ExecuteBatch(tx *sqlx.Tx, query string, after int64, end int64) (int64, error)
 	SetupMigrationTable(tx *sqlx.Tx) error
 }
 
 type migrationDao struct {
-	migrationTable string
+	migrationTable  string
+	historyTable    string
+	checkpointTable string
 }
 
 func NewMigrationDao(schema string) MigrationDao {
-	tableName := ""
+	tablePrefix := ""
 	if schema != "" {
-		tableName = schema + ".migration_log"
-	} else {
-		tableName = "migration_log"
+		tablePrefix = schema + "."
 	}
 
 	return &migrationDao{
-		migrationTable: tableName,
+		migrationTable:  tablePrefix + "migration_log",
+		historyTable:    tablePrefix + "migration_history",
+		checkpointTable: tablePrefix + "migration_checkpoint",
 	}
 }
 
+// Returns migration logs in no particular order, as the version order is decided by the migrator
 func (dao *migrationDao) GetMigrationLogs(tx *sqlx.Tx) ([]types.MigrationLog, error) {
 	mLogs := []types.MigrationLog{}
 
 	if err := tx.Select(&mLogs, "SELECT id, name, version, query, rollback, date, hash FROM "+dao.migrationTable); err != nil {
 		return nil, logger.WrapAndLogError(err, "error while getting migration logs from db")
 	}
-
-	sort.Slice(mLogs, func(i1, i2 int) bool {
-		return semver.CompareSemver(mLogs[i1].Version, mLogs[i2].Version, types.VERSION_SEPARATOR)
-	})
 	return mLogs, nil
 }
 
@@ -59,6 +67,15 @@ func (dao *migrationDao) InsertMigrationLog(tx *sqlx.Tx, mLog types.MigrationLog
 	return nil
 }
 
+// Updates name, query, rollback & hash of the migration log with the same version
+func (dao *migrationDao) UpdateMigrationLog(tx *sqlx.Tx, mLog types.MigrationLog) error {
+	_, err := tx.NamedExec("UPDATE "+dao.migrationTable+" SET name=:name, query=:query, rollback=:rollback, hash=:hash WHERE version=:version", mLog)
+	if err != nil {
+		return logger.LogError(fmt.Errorf("error in database while updating migration log\n%w", err))
+	}
+	return nil
+}
+
 func (dao *migrationDao) DeleteMigrationLog(tx *sqlx.Tx, mLog types.MigrationLog) error {
 	_, err := tx.NamedExec("DELETE FROM "+dao.migrationTable+" WHERE version=:version", mLog)
 	if err != nil {
@@ -67,20 +84,166 @@ func (dao *migrationDao) DeleteMigrationLog(tx *sqlx.Tx, mLog types.MigrationLog
 	return nil
 }
 
+func (dao *migrationDao) GetMigrationHistory(tx *sqlx.Tx, version string) ([]types.MigrationHistory, error) {
+	history := []types.MigrationHistory{}
+	query := "SELECT id, version, name, action, actor, date, hash FROM " + dao.historyTable
+	args := []any{}
+	if version != "" {
+		query += " WHERE version=?"
+		args = append(args, version)
+	}
+	if err := tx.Select(&history, tx.Rebind(query+" ORDER BY id"), args...); err != nil {
+		return nil, logger.WrapAndLogError(err, "error while getting migration history from db")
+	}
+	return history, nil
+}
+
+// Id of the history entry is generated by the database
+func (dao *migrationDao) InsertMigrationHistory(tx *sqlx.Tx, h types.MigrationHistory) error {
+	_, err := tx.NamedExec("INSERT INTO "+dao.historyTable+" (version, name, action, actor, date, hash) VALUES (:version, :name, :action, :actor, :date, :hash)", &h)
+	if err != nil {
+		return logger.LogError(fmt.Errorf("error in database while inserting migration history\n%w", err))
+	}
+	return nil
+}
+
 func (dao *migrationDao) ExecuteQuery(tx *sqlx.Tx, m types.Migration) error {
-	if _, err := tx.Exec(m.Query); err != nil {
+	if err := execScript(tx, m.Query); err != nil {
 		return logger.LogError(fmt.Errorf("error while executing query for migration '%v-%v'\n%w", m.Version, m.Name, err))
 	}
 	return nil
 }
 
 func (dao *migrationDao) ExecuteRollback(tx *sqlx.Tx, m types.Migration) error {
-	if _, err := tx.Exec(m.Rollback); err != nil {
+	if err := execScript(tx, m.Rollback); err != nil {
 		return logger.LogError(fmt.Errorf("error while executing rollback query for version '%v'\n%w", m.Version, err))
 	}
 	return nil
 }
 
+// Returns true, if first column of the first row returned by query is truthy
+func (dao *migrationDao) CheckPrecondition(tx *sqlx.Tx, query string) (bool, error) {
+	var result any
+	if err := tx.QueryRowx(query).Scan(&result); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return false, nil
+		}
+		return false, logger.LogError(fmt.Errorf("error while executing precondition query\n%w", err))
+	}
+	return isTruthy(result), nil
+}
+
+// Returns nil, if no checkpoint has been saved for the backfill
+func (dao *migrationDao) GetBackfillCheckpoint(tx *sqlx.Tx, name string) (*types.BackfillCheckpoint, error) {
+	c := types.BackfillCheckpoint{}
+	if err := tx.Get(&c, tx.Rebind("SELECT name, last_key, row_count, date FROM "+dao.checkpointTable+" WHERE name=?"), name); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, logger.LogError(fmt.Errorf("error while getting checkpoint for backfill '%v'\n%w", name, err))
+	}
+	return &c, nil
+}
+
+func (dao *migrationDao) SaveBackfillCheckpoint(tx *sqlx.Tx, c types.BackfillCheckpoint) error {
+	res, err := tx.NamedExec("UPDATE "+dao.checkpointTable+" SET last_key=:last_key, row_count=:row_count, date=:date WHERE name=:name", &c)
+	if err == nil {
+		var updated int64
+		if updated, err = res.RowsAffected(); err == nil && updated == 0 {
+			_, err = tx.NamedExec("INSERT INTO "+dao.checkpointTable+" (name, last_key, row_count, date) VALUES (:name, :last_key, :row_count, :date)", &c)
+		}
+	}
+	if err != nil {
+		return logger.LogError(fmt.Errorf("error in database while saving checkpoint for backfill '%v'\n%w", c.Name, err))
+	}
+	return nil
+}
+
+// Returns the largest key among the next size keys after the given key, or nil if no keys are left
+func (dao *migrationDao) GetBatchEnd(tx *sqlx.Tx, table string, keyColumn string, after int64, size int) (*int64, error) {
+	var end sql.NullInt64
+	query := fmt.Sprintf("SELECT MAX(%[1]v) FROM (SELECT %[1]v FROM %[2]v WHERE %[1]v > ? ORDER BY %[1]v LIMIT ?) batch", keyColumn, table)
+	if err := tx.Get(&end, tx.Rebind(query), after, size); err != nil {
+		return nil, logger.LogError(fmt.Errorf("error while getting next batch of %v.%v after %v\n%w", table, keyColumn, after, err))
+	}
+	if !end.Valid {
+		return nil, nil
+	}
+	return &end.Int64, nil
+}
+
+// Executes a batch query, with the exclusive lower & inclusive upper key of the batch as args. Returns the number of rows affected
+func (dao *migrationDao) ExecuteBatch(tx *sqlx.Tx, query string, after int64, end int64) (int64, error) {
+	res, err := tx.Exec(tx.Rebind(query), after, end)
+	if err != nil {
+		return 0, logger.LogError(fmt.Errorf("error while executing batch (%v, %v]\n%w", after, end, err))
+	}
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return 0, logger.LogError(fmt.Errorf("error while getting rows affected by batch (%v, %v]\n%w", after, end, err))
+	}
+	return rows, nil
+}
+
+func isTruthy(val any) bool {
+	switch v := val.(type) {
+	case nil:
+		return false
+	case bool:
+		return v
+	case int64:
+		return v != 0
+	case float64:
+		return v != 0
+	case []byte:
+		return isTruthyString(string(v))
+	case string:
+		return isTruthyString(v)
+	default:
+		return true
+	}
+}
+
+func isTruthyString(s string) bool {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "", "0", "f", "false":
+		return false
+	default:
+		return true
+	}
+}
+
+// Executes statements of the script one by one, as some drivers allow only a single statement per Exec
+func execScript(tx *sqlx.Tx, script string) error {
+	statements, splitErr := SplitStatementsWithOptions(script, SplitOptions{BackslashEscapes: tx.DriverName() == "mysql"})
+	if splitErr != nil {
+		return fmt.Errorf("error while splitting script into statements\n%w", splitErr)
+	}
+	if len(statements) == 0 {
+		// Let the driver decide, how an empty script has to be treated
+		_, err := tx.Exec(script)
+		return err
+	}
+	for i, stmt := range statements {
+		if _, err := tx.Exec(stmt.Query); err != nil {
+			return fmt.Errorf("error in statement %v at line %v\n%w", i+1, stmt.Line, err)
+		}
+	}
+	return nil
+}
+
+// Auto generated integer primary key. INTEGER PRIMARY KEY is an alias of rowid in sqlite
+func identityColumn(driverName string) string {
+	switch driverName {
+	case "postgres", "pgx", "pq":
+		return "BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY"
+	case "mysql":
+		return "BIGINT AUTO_INCREMENT PRIMARY KEY"
+	default:
+		return "INTEGER PRIMARY KEY"
+	}
+}
+
 func (dao *migrationDao) SetupMigrationTable(tx *sqlx.Tx) error {
 	_, err := tx.Exec(`CREATE TABLE IF NOT EXISTS ` + dao.migrationTable + ` (
 		id INTEGER PRIMARY KEY,
@@ -94,5 +257,26 @@ func (dao *migrationDao) SetupMigrationTable(tx *sqlx.Tx) error {
 	if err != nil {
 		return logger.LogError(fmt.Errorf("error in creating migration_log table\n%w", err))
 	}
+	_, err = tx.Exec(`CREATE TABLE IF NOT EXISTS ` + dao.historyTable + ` (
+		id ` + identityColumn(tx.DriverName()) + `,
+		version VARCHAR(20),
+		name VARCHAR(200),
+		action VARCHAR(20),
+		actor VARCHAR(200),
+		date BIGINT,
+		hash VARCHAR(64)
+	);`)
+	if err != nil {
+		return logger.LogError(fmt.Errorf("error in creating migration_history table\n%w", err))
+	}
+	_, err = tx.Exec(`CREATE TABLE IF NOT EXISTS ` + dao.checkpointTable + ` (
+		name VARCHAR(200) PRIMARY KEY,
+		last_key BIGINT,
+		row_count BIGINT,
+		date BIGINT
+	);`)
+	if err != nil {
+		return logger.LogError(fmt.Errorf("error in creating migration_checkpoint table\n%w", err))
+	}
 	return nil
 }
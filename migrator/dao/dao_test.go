@@ -2,9 +2,13 @@ package dao
 
 import (
 	"bytes"
+	"database/sql"
+	"slices"
 	"testing"
 
 	"github.com/jmoiron/sqlx"
+	"github.com/mattn/go-sqlite3"
+	"github.com/samber/lo"
 	"github.com/stretchr/testify/assert"
 	"github.com/wizards-0/go-pins/logger"
 	"github.com/wizards-0/go-pins/migrator/types"
@@ -39,10 +43,18 @@ func TestCrud(t *testing.T) {
 		_ = dao.InsertMigrationLog(tx, l2)
 		_ = dao.InsertMigrationLog(tx, l1)
 		mLogs, _ := dao.GetMigrationLogs(tx)
-		assert.Equal(3, len(mLogs))
-		assert.Equal("1", mLogs[0].Version)
-		assert.Equal("1-1", mLogs[1].Version)
-		assert.Equal("2", mLogs[2].Version)
+		assert.ElementsMatch([]string{"1", "1-1", "2"}, lo.Map(mLogs, func(mLog types.MigrationLog, _ int) string {
+			return mLog.Version
+		}))
+
+		l2.Query = "SELECT 2"
+		l2.Hash = "h2"
+		assert.Nil(dao.UpdateMigrationLog(tx, l2))
+		mLogs, _ = dao.GetMigrationLogs(tx)
+		updated, _ := lo.Find(mLogs, func(mLog types.MigrationLog) bool {
+			return mLog.Version == "2"
+		})
+		assert.Equal(l2, updated)
 
 		dao.DeleteMigrationLog(tx, l1)
 		mLogs, _ = dao.GetMigrationLogs(tx)
@@ -85,6 +97,17 @@ func TestInsertMigrationLogsError(t *testing.T) {
 	})
 }
 
+func TestUpdateMigrationLogError(t *testing.T) {
+	assert := assert.New(t)
+	setup()
+	slu.WithDefaultCtxTx(db, func(tx *sqlx.Tx) bool {
+		tx.Rollback()
+		err := dao.UpdateMigrationLog(tx, types.MigrationLog{})
+		assert.ErrorContains(err, "error in database while updating migration log")
+		return false
+	})
+}
+
 func TestDeleteMigrationLogError(t *testing.T) {
 	assert := assert.New(t)
 	setup()
@@ -96,6 +119,133 @@ func TestDeleteMigrationLogError(t *testing.T) {
 	})
 }
 
+func TestMigrationHistory(t *testing.T) {
+	assert := assert.New(t)
+	setup()
+	slu.WithDefaultCtxTx(db, func(tx *sqlx.Tx) bool {
+		_ = dao.InsertMigrationHistory(tx, types.MigrationHistory{Version: "1", Action: types.HISTORY_ACTION_APPLY, Actor: "test"})
+		_ = dao.InsertMigrationHistory(tx, types.MigrationHistory{Version: "2", Action: types.HISTORY_ACTION_APPLY, Actor: "test"})
+		_ = dao.InsertMigrationHistory(tx, types.MigrationHistory{Version: "1", Action: types.HISTORY_ACTION_ROLLBACK, Actor: "test"})
+		history, err := dao.GetMigrationHistory(tx, "")
+		assert.Nil(err)
+		assert.Equal(3, len(history))
+		assert.Equal(3, history[2].Id)
+		assert.Equal(types.HISTORY_ACTION_ROLLBACK, history[2].Action)
+
+		history, err = dao.GetMigrationHistory(tx, "1")
+		assert.Nil(err)
+		assert.Equal(2, len(history))
+		return false
+	})
+}
+
+func TestMigrationHistoryError(t *testing.T) {
+	assert := assert.New(t)
+	setup()
+	slu.WithDefaultCtxTx(db, func(tx *sqlx.Tx) bool {
+		tx.Rollback()
+		err := dao.InsertMigrationHistory(tx, types.MigrationHistory{})
+		assert.ErrorContains(err, "error in database while inserting migration history")
+		_, err = dao.GetMigrationHistory(tx, "")
+		assert.ErrorContains(err, "error while getting migration history")
+		return false
+	})
+
+	historyDao := NewMigrationDao("").(*migrationDao)
+	historyDao.historyTable = "migration_log"
+	slu.WithDefaultCtxTx(db, func(tx *sqlx.Tx) bool {
+		err := historyDao.InsertMigrationHistory(tx, types.MigrationHistory{})
+		assert.ErrorContains(err, "error in database while inserting migration history")
+		return false
+	})
+}
+
+func TestDollarPlaceholders(t *testing.T) {
+	assert := assert.New(t)
+	dollarDb := getDollarDbConnection()
+	defer dollarDb.Close()
+	slu.WithDefaultCtxTx(dollarDb, func(tx *sqlx.Tx) bool {
+		assert.Nil(dao.SetupMigrationTable(tx))
+		assert.Nil(dao.InsertMigrationHistory(tx, types.MigrationHistory{Version: "1", Action: types.HISTORY_ACTION_APPLY, Actor: "test"}))
+		assert.Nil(dao.InsertMigrationHistory(tx, types.MigrationHistory{Version: "2", Action: types.HISTORY_ACTION_APPLY, Actor: "test"}))
+		history, err := dao.GetMigrationHistory(tx, "2")
+		assert.Nil(err)
+		assert.Equal(1, len(history))
+		assert.Equal(2, history[0].Id)
+
+		assert.Nil(dao.SaveBackfillCheckpoint(tx, types.BackfillCheckpoint{Name: "b1", LastKey: 2, Rows: 2}))
+		c, err := dao.GetBackfillCheckpoint(tx, "b1")
+		assert.Nil(err)
+		assert.Equal(int64(2), c.Rows)
+		tx.MustExec("CREATE TABLE BATCH(ID INT)")
+		tx.MustExec("INSERT INTO BATCH VALUES (1), (2), (3)")
+		end, err := dao.GetBatchEnd(tx, "BATCH", "ID", 1, 5)
+		assert.Nil(err)
+		assert.Equal(int64(3), *end)
+		rows, err := dao.ExecuteBatch(tx, "DELETE FROM BATCH WHERE ID > ? AND ID <= ?", 1, 3)
+		assert.Nil(err)
+		assert.Equal(int64(2), rows)
+		return false
+	})
+}
+
+func TestIdentityColumn(t *testing.T) {
+	assert := assert.New(t)
+	assert.Equal("INTEGER PRIMARY KEY", identityColumn("sqlite3"))
+	assert.Equal("BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY", identityColumn("postgres"))
+	assert.Equal("BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY", identityColumn("pgx"))
+	assert.Equal("BIGINT AUTO_INCREMENT PRIMARY KEY", identityColumn("mysql"))
+}
+
+func TestBackfillCheckpoint(t *testing.T) {
+	assert := assert.New(t)
+	setup()
+	slu.WithDefaultCtxTx(db, func(tx *sqlx.Tx) bool {
+		c, err := dao.GetBackfillCheckpoint(tx, "b1")
+		assert.Nil(err)
+		assert.Nil(c)
+
+		assert.Nil(dao.SaveBackfillCheckpoint(tx, types.BackfillCheckpoint{Name: "b1", LastKey: 10, Rows: 5, Date: 1}))
+		assert.Nil(dao.SaveBackfillCheckpoint(tx, types.BackfillCheckpoint{Name: "b1", LastKey: 20, Rows: 9, Date: 2}))
+		c, err = dao.GetBackfillCheckpoint(tx, "b1")
+		assert.Nil(err)
+		assert.Equal(types.BackfillCheckpoint{Name: "b1", LastKey: 20, Rows: 9, Date: 2}, *c)
+
+		tx.Rollback()
+		_, err = dao.GetBackfillCheckpoint(tx, "b1")
+		assert.ErrorContains(err, "error while getting checkpoint for backfill 'b1'")
+		err = dao.SaveBackfillCheckpoint(tx, types.BackfillCheckpoint{Name: "b1"})
+		assert.ErrorContains(err, "error in database while saving checkpoint for backfill 'b1'")
+		return false
+	})
+}
+
+func TestBatches(t *testing.T) {
+	assert := assert.New(t)
+	setup()
+	slu.WithDefaultCtxTx(db, func(tx *sqlx.Tx) bool {
+		tx.MustExec("CREATE TABLE BATCH_TEST(ID INTEGER PRIMARY KEY, VAL INT)")
+		tx.MustExec("INSERT INTO BATCH_TEST(ID) VALUES (2), (4), (6), (8), (10)")
+		end, err := dao.GetBatchEnd(tx, "BATCH_TEST", "ID", 2, 3)
+		assert.Nil(err)
+		assert.Equal(int64(8), *end)
+
+		rows, err := dao.ExecuteBatch(tx, "UPDATE BATCH_TEST SET VAL = 1 WHERE ID > ? AND ID <= ?", 2, *end)
+		assert.Nil(err)
+		assert.Equal(int64(3), rows)
+
+		end, err = dao.GetBatchEnd(tx, "BATCH_TEST", "ID", 10, 3)
+		assert.Nil(err)
+		assert.Nil(end)
+
+		_, err = dao.GetBatchEnd(tx, "MISSING_TABLE", "ID", 0, 3)
+		assert.ErrorContains(err, "error while getting next batch of MISSING_TABLE.ID after 0")
+		_, err = dao.ExecuteBatch(tx, "UPDATE MISSING_TABLE SET VAL = 1 WHERE ID > ? AND ID <= ?", 0, 1)
+		assert.ErrorContains(err, "error while executing batch (0, 1]")
+		return false
+	})
+}
+
 func TestExecQuery(t *testing.T) {
 	assert := assert.New(t)
 	setup()
@@ -111,6 +261,53 @@ func TestExecQuery(t *testing.T) {
 	})
 }
 
+func TestExecQueryMultipleStatements(t *testing.T) {
+	assert := assert.New(t)
+	setup()
+	slu.WithDefaultCtxTx(db, func(tx *sqlx.Tx) bool {
+		err := dao.ExecuteQuery(tx, types.Migration{Query: "CREATE TABLE USER(ID INT,NAME TEXT);\nINSERT INTO USER VALUES (1, 'a;b');"})
+		assert.Nil(err)
+		count := 0
+		tx.Get(&count, "SELECT COUNT(*) FROM USER")
+		assert.Equal(1, count)
+
+		err = dao.ExecuteQuery(tx, types.Migration{Version: "2", Name: "bad", Query: "INSERT INTO USER VALUES (2, 'b');\n\nINSERT INTO NO_TABLE VALUES (1);"})
+		assert.ErrorContains(err, "error while executing query for migration '2-bad'")
+		assert.ErrorContains(err, "error in statement 2 at line 3")
+
+		err = dao.ExecuteRollback(tx, types.Migration{Version: "2", Rollback: "SELECT 'abc"})
+		assert.ErrorContains(err, "error while splitting script into statements")
+		return false
+	})
+}
+
+func TestCheckPrecondition(t *testing.T) {
+	assert := assert.New(t)
+	setup()
+	slu.WithDefaultCtxTx(db, func(tx *sqlx.Tx) bool {
+		for query, expected := range map[string]bool{
+			"SELECT 1":                           true,
+			"SELECT 0":                           false,
+			"SELECT 0.5":                         true,
+			"SELECT NULL":                        false,
+			"SELECT 'yes'":                       true,
+			"SELECT 'false'":                     false,
+			"SELECT 1 WHERE 1 = 0":               false,
+			"SELECT COUNT(*) FROM migration_log": false,
+			"SELECT x'31'":                       true,
+		} {
+			holds, err := dao.CheckPrecondition(tx, query)
+			assert.Nil(err)
+			assert.Equal(expected, holds, query)
+		}
+		_, err := dao.CheckPrecondition(tx, "SELECT * FROM NO_TABLE")
+		assert.ErrorContains(err, "error while executing precondition query")
+		return false
+	})
+	assert.True(isTruthy(true))
+	assert.True(isTruthy(struct{}{}))
+}
+
 func TestRollback(t *testing.T) {
 	assert := assert.New(t)
 	setup()
@@ -128,9 +325,11 @@ func TestSchema(t *testing.T) {
 	assert := assert.New(t)
 	dao := NewMigrationDao("").(*migrationDao)
 	assert.Equal("migration_log", dao.migrationTable)
+	assert.Equal("migration_history", dao.historyTable)
 
 	dao = NewMigrationDao("my_schema").(*migrationDao)
 	assert.Equal("my_schema.migration_log", dao.migrationTable)
+	assert.Equal("my_schema.migration_history", dao.historyTable)
 
 }
 
@@ -141,3 +340,16 @@ func getDbConnection() *sqlx.DB {
 	}
 	return db
 }
+
+// Sqlite connection, which binds args with $1, $2... like postgres
+func getDollarDbConnection() *sqlx.DB {
+	if !slices.Contains(sql.Drivers(), "sqlite3-dollar") {
+		sql.Register("sqlite3-dollar", &sqlite3.SQLiteDriver{})
+		sqlx.BindDriver("sqlite3-dollar", sqlx.DOLLAR)
+	}
+	db, err := sqlx.Open("sqlite3-dollar", "file:dollar-db?mode=memory&cache=shared")
+	if err != nil {
+		panic(err)
+	}
+	return db
+}
@@ -0,0 +1,290 @@
+package dao
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+type Statement struct {
+	Query string
+	Line  int
+}
+
+// Splits a sql script into individual statements on top level semicolons.
+// Semicolons inside string literals, quoted identifiers, dollar quoted strings, comments
+// and BEGIN ... END / CASE ... END blocks (e.g. trigger bodies) do not end a statement.
+// BEGIN opens a block only in the body of a routine or trigger, outside parentheses, as it is a valid identifier elsewhere.
+// Comments preceding a statement are dropped. Line is the 1 based line number in script, where the statement starts.
+func SplitStatements(script string) ([]Statement, error) {
+	return SplitStatementsWithOptions(script, SplitOptions{})
+}
+
+type SplitOptions struct {
+	// Backslash escapes the next character in string literals, e.g. 'it\'s', as in MySQL by default
+	BackslashEscapes bool
+}
+
+func SplitStatementsWithOptions(script string, opts SplitOptions) ([]Statement, error) {
+	s := &splitter{src: []rune(script), line: 1, opts: opts}
+	return s.split()
+}
+
+type splitter struct {
+	opts       SplitOptions
+	src        []rune
+	pos        int
+	line       int
+	statements []Statement
+
+	stmtStart int
+	stmtLine  int
+	hasToken  bool
+	firstWord string
+	// Set once the statement is known to define a routine or trigger, whose body can be a BEGIN ... END block
+	routine bool
+	parens  int
+	// Parenthesis nesting of each open block, so END only closes a block opened at the same nesting
+	blocks []int
+}
+
+func (s *splitter) split() ([]Statement, error) {
+	for s.pos < len(s.src) {
+		c := s.src[s.pos]
+		switch {
+		case c == '\n':
+			s.line++
+			s.pos++
+		case unicode.IsSpace(c):
+			s.pos++
+		case c == '-' && s.peek(1) == '-':
+			s.skipLineComment()
+		case c == '/' && s.peek(1) == '*':
+			if err := s.skipBlockComment(); err != nil {
+				return nil, err
+			}
+		case c == '\'' || c == '"' || c == '`':
+			s.markToken()
+			if err := s.skipQuoted(c); err != nil {
+				return nil, err
+			}
+		case c == '$' && s.isDollarQuoteStart():
+			s.markToken()
+			if err := s.skipDollarQuoted(); err != nil {
+				return nil, err
+			}
+		case c == ';':
+			if len(s.blocks) > 0 {
+				s.pos++
+			} else {
+				s.endStatement(s.pos)
+				s.pos++
+			}
+		case isWordRune(c):
+			s.markToken()
+			s.readWord()
+		case c == '(':
+			s.markToken()
+			s.parens++
+			s.pos++
+		case c == ')':
+			s.markToken()
+			s.parens = max(s.parens-1, 0)
+			s.pos++
+		default:
+			s.markToken()
+			s.pos++
+		}
+	}
+	s.endStatement(len(s.src))
+	return s.statements, nil
+}
+
+func (s *splitter) peek(offset int) rune {
+	if s.pos+offset < len(s.src) {
+		return s.src[s.pos+offset]
+	}
+	return 0
+}
+
+func (s *splitter) markToken() {
+	if !s.hasToken {
+		s.hasToken = true
+		s.stmtStart = s.pos
+		s.stmtLine = s.line
+	}
+}
+
+func (s *splitter) endStatement(end int) {
+	if s.hasToken {
+		s.statements = append(s.statements, Statement{
+			Query: strings.TrimSpace(string(s.src[s.stmtStart:end])),
+			Line:  s.stmtLine,
+		})
+	}
+	s.hasToken = false
+	s.firstWord = ""
+	s.routine = false
+	s.parens = 0
+	s.blocks = nil
+}
+
+func (s *splitter) skipLineComment() {
+	for s.pos < len(s.src) && s.src[s.pos] != '\n' {
+		s.pos++
+	}
+}
+
+func (s *splitter) skipBlockComment() error {
+	startLine := s.line
+	s.pos += 2
+	for s.pos < len(s.src) {
+		if s.src[s.pos] == '*' && s.peek(1) == '/' {
+			s.pos += 2
+			return nil
+		}
+		if s.src[s.pos] == '\n' {
+			s.line++
+		}
+		s.pos++
+	}
+	return fmt.Errorf("unterminated block comment starting at line %v", startLine)
+}
+
+// Quotes are escaped by doubling them, e.g.
+//
+//	'it''s'
+//
+// or with a backslash inside string literals, if enabled by SplitOptions.BackslashEscapes
+func (s *splitter) skipQuoted(quote rune) error {
+	startLine := s.line
+	s.pos++
+	for s.pos < len(s.src) {
+		c := s.src[s.pos]
+		if c == '\n' {
+			s.line++
+		}
+		s.pos++
+		if c == '\\' && quote != '`' && s.opts.BackslashEscapes && s.pos < len(s.src) {
+			if s.src[s.pos] == '\n' {
+				s.line++
+			}
+			s.pos++
+			continue
+		}
+		if c == quote {
+			if s.pos < len(s.src) && s.src[s.pos] == quote {
+				s.pos++
+				continue
+			}
+			return nil
+		}
+	}
+	return fmt.Errorf("unterminated quoted string (%c) starting at line %v", quote, startLine)
+}
+
+func (s *splitter) isDollarQuoteStart() bool {
+	_, ok := s.dollarTag()
+	return ok
+}
+
+// Returns the tag at current position, e.g. $$ or $body$
+func (s *splitter) dollarTag() (string, bool) {
+	for i := s.pos + 1; i < len(s.src); i++ {
+		c := s.src[i]
+		if c == '$' {
+			return string(s.src[s.pos : i+1]), true
+		}
+		if !(c == '_' || unicode.IsLetter(c) || (i > s.pos+1 && unicode.IsDigit(c))) {
+			return "", false
+		}
+	}
+	return "", false
+}
+
+func (s *splitter) skipDollarQuoted() error {
+	startLine := s.line
+	tag, _ := s.dollarTag()
+	tagRunes := []rune(tag)
+	s.pos += len(tagRunes)
+	for s.pos < len(s.src) {
+		if s.src[s.pos] == '$' && s.pos+len(tagRunes) <= len(s.src) && string(s.src[s.pos:s.pos+len(tagRunes)]) == tag {
+			s.pos += len(tagRunes)
+			return nil
+		}
+		if s.src[s.pos] == '\n' {
+			s.line++
+		}
+		s.pos++
+	}
+	return fmt.Errorf("unterminated dollar quoted string %v starting at line %v", tag, startLine)
+}
+
+func (s *splitter) readWord() {
+	start := s.pos
+	for s.pos < len(s.src) && isWordRune(s.src[s.pos]) {
+		s.pos++
+	}
+	word := strings.ToUpper(string(s.src[start:s.pos]))
+	if s.firstWord == "" {
+		s.firstWord = word
+		// A statement starting with BEGIN is a transaction statement, not a block. DECLARE starts an anonymous block
+		s.routine = word == "DECLARE"
+		return
+	}
+	switch word {
+	case "TRIGGER", "FUNCTION", "PROCEDURE", "EVENT":
+		if s.parens == 0 && (s.firstWord == "CREATE" || s.firstWord == "ALTER") {
+			s.routine = true
+		}
+	case "BEGIN":
+		// Elsewhere BEGIN is an identifier, e.g. a column named begin
+		if s.parens == 0 && (s.routine || len(s.blocks) > 0) {
+			s.blocks = append(s.blocks, s.parens)
+		}
+	case "CASE":
+		s.blocks = append(s.blocks, s.parens)
+	case "END":
+		if len(s.blocks) == 0 || s.blocks[len(s.blocks)-1] != s.parens {
+			// Not closing a block, e.g. a column named end
+			return
+		}
+		next, end := s.nextWord()
+		switch next {
+		case "IF", "LOOP", "WHILE", "REPEAT", "FOR":
+			// Closes a construct which did not open a block, e.g. END IF
+		case "CASE":
+			// END CASE closes the CASE block, and its CASE does not open another one
+			s.advanceTo(end)
+			s.blocks = s.blocks[:len(s.blocks)-1]
+		default:
+			s.blocks = s.blocks[:len(s.blocks)-1]
+		}
+	}
+}
+
+// Returns the upper cased word following current position, and the position after it
+func (s *splitter) nextWord() (string, int) {
+	i := s.pos
+	for i < len(s.src) && unicode.IsSpace(s.src[i]) {
+		i++
+	}
+	start := i
+	for i < len(s.src) && isWordRune(s.src[i]) {
+		i++
+	}
+	return strings.ToUpper(string(s.src[start:i])), i
+}
+
+// Moves to end, counting the lines skipped over
+func (s *splitter) advanceTo(end int) {
+	for ; s.pos < end; s.pos++ {
+		if s.src[s.pos] == '\n' {
+			s.line++
+		}
+	}
+}
+
+func isWordRune(c rune) bool {
+	return c == '_' || unicode.IsLetter(c) || unicode.IsDigit(c)
+}
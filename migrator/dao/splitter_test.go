@@ -0,0 +1,147 @@
+package dao
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSplitStatements(t *testing.T) {
+	assert := assert.New(t)
+	statements, err := SplitStatements("CREATE TABLE A(ID INT);\n\nINSERT INTO A VALUES (1);\nINSERT INTO A VALUES (2)")
+	assert.Nil(err)
+	assert.Equal([]Statement{
+		{Query: "CREATE TABLE A(ID INT)", Line: 1},
+		{Query: "INSERT INTO A VALUES (1)", Line: 3},
+		{Query: "INSERT INTO A VALUES (2)", Line: 4},
+	}, statements)
+
+	statements, err = SplitStatements("")
+	assert.Nil(err)
+	assert.Equal(0, len(statements))
+
+	statements, err = SplitStatements(" ;\n -- only a comment;\n /* another; one */ ;")
+	assert.Nil(err)
+	assert.Equal(0, len(statements))
+}
+
+func TestSplitStatementsLiterals(t *testing.T) {
+	assert := assert.New(t)
+	statements, err := SplitStatements(`INSERT INTO A VALUES ('a;b', 'it''s;');
+SELECT "weird;column", ` + "`other;col`" + ` FROM A; -- trailing; comment
+/* block;
+comment */ SELECT 1;`)
+	assert.Nil(err)
+	assert.Equal(3, len(statements))
+	assert.Equal("INSERT INTO A VALUES ('a;b', 'it''s;')", statements[0].Query)
+	assert.Equal(2, statements[1].Line)
+	assert.Equal(4, statements[2].Line)
+	assert.Equal("SELECT 1", statements[2].Query)
+}
+
+func TestSplitStatementsBackslashEscapes(t *testing.T) {
+	assert := assert.New(t)
+	script := "INSERT INTO a VALUES ('it\\'s;', \"a\\\\\");\nSELECT `b\\`;"
+	statements, err := SplitStatementsWithOptions(script, SplitOptions{BackslashEscapes: true})
+	assert.Nil(err)
+	assert.Equal([]Statement{
+		{Query: "INSERT INTO a VALUES ('it\\'s;', \"a\\\\\")", Line: 1},
+		{Query: "SELECT `b\\`", Line: 2},
+	}, statements)
+
+	_, err = SplitStatements(script)
+	assert.ErrorContains(err, "unterminated quoted string")
+}
+
+func TestSplitStatementsDollarQuoting(t *testing.T) {
+	assert := assert.New(t)
+	statements, err := SplitStatements(`CREATE FUNCTION f() RETURNS INT AS $$ SELECT 1; $$ LANGUAGE SQL;
+CREATE FUNCTION g() RETURNS INT AS $body$
+BEGIN
+	RETURN $1;
+END;
+$body$ LANGUAGE plpgsql;`)
+	assert.Nil(err)
+	assert.Equal(2, len(statements))
+	assert.Equal(2, statements[1].Line)
+	assert.Contains(statements[1].Query, "$body$ LANGUAGE plpgsql")
+}
+
+func TestSplitStatementsBlocks(t *testing.T) {
+	assert := assert.New(t)
+	statements, err := SplitStatements(`BEGIN TRANSACTION;
+CREATE TRIGGER t AFTER INSERT ON A
+BEGIN
+	UPDATE B SET C = CASE WHEN NEW.ID > 1 THEN 1 ELSE 0 END;
+	INSERT INTO LOG VALUES (NEW.ID);
+END;
+CREATE PROCEDURE p()
+BEGIN
+	IF 1 = 1 THEN
+		SELECT 1;
+	END IF;
+	SELECT 2;
+END;
+COMMIT;`)
+	assert.Nil(err)
+	assert.Equal(4, len(statements))
+	assert.Equal("BEGIN TRANSACTION", statements[0].Query)
+	assert.Equal(2, statements[1].Line)
+	assert.Contains(statements[1].Query, "INSERT INTO LOG VALUES (NEW.ID);\nEND")
+	assert.Equal(7, statements[2].Line)
+	assert.Contains(statements[2].Query, "SELECT 2;\nEND")
+	assert.Equal("COMMIT", statements[3].Query)
+}
+
+func TestSplitStatementsBeginIdentifier(t *testing.T) {
+	assert := assert.New(t)
+	statements, err := SplitStatements(`CREATE TABLE a (id INT, begin INT, end INT);
+INSERT INTO a VALUES(1, 2, 3);
+SELECT begin, CASE WHEN id > 1 THEN (end) ELSE 0 END FROM a;
+CREATE TRIGGER t AFTER INSERT ON a FOR EACH ROW
+BEGIN
+	INSERT INTO b (begin, end) VALUES (NEW.begin, NEW.end);
+END;
+SELECT 1;`)
+	assert.Nil(err)
+	assert.Equal(5, len(statements))
+	assert.Equal(Statement{Query: "INSERT INTO a VALUES(1, 2, 3)", Line: 2}, statements[1])
+	assert.Equal(4, statements[3].Line)
+	assert.Equal(Statement{Query: "SELECT 1", Line: 8}, statements[4])
+}
+
+func TestSplitStatementsEndCase(t *testing.T) {
+	assert := assert.New(t)
+	statements, err := SplitStatements(`CREATE PROCEDURE p(IN x INT)
+BEGIN
+	CASE x
+		WHEN 1 THEN SELECT 1;
+		ELSE SELECT 2;
+	END CASE;
+	SELECT 3;
+END;
+SELECT 4;
+SELECT 5;`)
+	assert.Nil(err)
+	assert.Equal(3, len(statements))
+	assert.Contains(statements[0].Query, "END CASE;\n\tSELECT 3;\nEND")
+	assert.Equal(Statement{Query: "SELECT 4", Line: 9}, statements[1])
+	assert.Equal(Statement{Query: "SELECT 5", Line: 10}, statements[2])
+
+	statements, err = SplitStatements("CREATE PROCEDURE p(IN x INT)\nBEGIN\n\tCASE x WHEN 1 THEN SELECT 1; END\n\tCASE;\nEND;\nSELECT 2;")
+	assert.Nil(err)
+	assert.Equal(2, len(statements))
+	assert.Equal(Statement{Query: "SELECT 2", Line: 6}, statements[1])
+}
+
+func TestSplitStatementsErrors(t *testing.T) {
+	assert := assert.New(t)
+	_, err := SplitStatements("SELECT 1;\nSELECT 'abc")
+	assert.ErrorContains(err, "unterminated quoted string (') starting at line 2")
+
+	_, err = SplitStatements("SELECT 1; /* abc")
+	assert.ErrorContains(err, "unterminated block comment starting at line 1")
+
+	_, err = SplitStatements("SELECT $tag$ abc $$;")
+	assert.ErrorContains(err, "unterminated dollar quoted string $tag$")
+}
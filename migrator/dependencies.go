@@ -0,0 +1,142 @@
+package migrator
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/wizards-0/go-pins/migrator/types"
+	"github.com/wizards-0/go-pins/semver"
+)
+
+const DIRECTIVE_DEPENDS = "depends"
+
+// Versions, which have to be applied before the migration. Declared as '-- migrator:depends 2-3, 2-4'
+func parseDependencies(m types.Migration) ([]string, error) {
+	dependencies := []string{}
+	for _, d := range parseDirectives(m.Query) {
+		if d.name != DIRECTIVE_DEPENDS {
+			continue
+		}
+		versions := strings.FieldsFunc(d.value, func(r rune) bool {
+			return r == ',' || r == ' ' || r == '\t'
+		})
+		if len(versions) == 0 {
+			return nil, fmt.Errorf("missing dependency versions at line %v for migration '%v-%v'", d.line, m.Version, m.Name)
+		}
+		dependencies = append(dependencies, versions...)
+	}
+	return dependencies, nil
+}
+
+// Orders migrations, so that each one comes after the versions it depends on.
+// Migrations without a dependency between them are ordered by cmp, so migrations without dependencies keep the version order.
+// Dependencies on applied versions are already satisfied
+func orderMigrations(mArr []types.Migration, applied map[string]bool, cmp semver.Comparator) ([]types.Migration, error) {
+	byVersion := map[string]types.Migration{}
+	for _, m := range mArr {
+		byVersion[m.Version] = m
+	}
+	dependents := map[string][]string{}
+	pendingDeps := map[string]int{}
+	errs := []error{}
+	for _, m := range mArr {
+		dependencies, err := parseDependencies(m)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		for _, dep := range dependencies {
+			if _, exists := byVersion[dep]; exists {
+				dependents[dep] = append(dependents[dep], m.Version)
+				pendingDeps[m.Version]++
+			} else if !applied[dep] {
+				errs = append(errs, fmt.Errorf("migration '%v-%v' depends on version %v, which is not found", m.Version, m.Name, dep))
+			}
+		}
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	ready := []string{}
+	for _, m := range mArr {
+		if pendingDeps[m.Version] == 0 {
+			ready = append(ready, m.Version)
+		}
+	}
+	ordered := []types.Migration{}
+	for len(ready) > 0 {
+		next := slices.MinFunc(ready, cmp.Compare)
+		ready = slices.DeleteFunc(ready, func(v string) bool {
+			return v == next
+		})
+		ordered = append(ordered, byVersion[next])
+		for _, dependent := range dependents[next] {
+			pendingDeps[dependent]--
+			if pendingDeps[dependent] == 0 {
+				ready = append(ready, dependent)
+			}
+		}
+	}
+	if len(ordered) < len(byVersion) {
+		cyclic := []string{}
+		for v, count := range pendingDeps {
+			if count > 0 {
+				cyclic = append(cyclic, v)
+			}
+		}
+		slices.SortFunc(cyclic, cmp.Compare)
+		return nil, fmt.Errorf("found dependency cycle, following versions cannot be ordered: %v", strings.Join(cyclic, ", "))
+	}
+	return ordered, nil
+}
+
+// Returns migration logs with version >= ver, ordered so that each one is rolled back before the versions it depends on.
+// Fails if a migration which is not rolled back, depends on one which is
+func orderRollback(ver string, mLogs []types.MigrationLog, cmp semver.Comparator) ([]types.MigrationLog, error) {
+	selected := map[string]types.MigrationLog{}
+	remaining := map[string]bool{}
+	for _, mLog := range mLogs {
+		if cmp.Compare(ver, mLog.Version) <= 0 {
+			selected[mLog.Version] = mLog
+		} else {
+			remaining[mLog.Version] = true
+		}
+	}
+	errs := []error{}
+	for _, mLog := range mLogs {
+		if !remaining[mLog.Version] {
+			continue
+		}
+		dependencies, err := parseDependencies(mLog.Migration)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		for _, dep := range dependencies {
+			if _, exists := selected[dep]; exists {
+				errs = append(errs, fmt.Errorf("cannot rollback version %v, migration '%v-%v' depends on it", dep, mLog.Version, mLog.Name))
+			}
+		}
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	mArr := []types.Migration{}
+	for _, mLog := range selected {
+		mArr = append(mArr, mLog.Migration)
+	}
+	ordered, err := orderMigrations(mArr, remaining, cmp)
+	if err != nil {
+		return nil, err
+	}
+	slices.Reverse(ordered)
+	result := []types.MigrationLog{}
+	for _, m := range ordered {
+		result = append(result, selected[m.Version])
+	}
+	return result, nil
+}
@@ -0,0 +1,155 @@
+package migrator
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+	"github.com/wizards-0/go-pins/migrator/types"
+	"github.com/wizards-0/go-pins/semver"
+)
+
+func dependentMigration(version string, header string) types.Migration {
+	table := "T_" + strings.ReplaceAll(version, "-", "_")
+	return types.Migration{
+		Name:     "table-" + version,
+		Version:  version,
+		Query:    header + "\nCREATE TABLE IF NOT EXISTS " + table + "(Id int);",
+		Rollback: "DROP TABLE IF EXISTS " + table + ";",
+	}
+}
+
+func versions(mArr []types.Migration) []string {
+	return lo.Map(mArr, func(m types.Migration, _ int) string {
+		return m.Version
+	})
+}
+
+func TestParseDependencies(t *testing.T) {
+	assert := assert.New(t)
+	deps, err := parseDependencies(dependentMigration("3", "-- migrator:depends 2-3, 2-4\n-- migrator:depends 1"))
+	assert.Nil(err)
+	assert.Equal([]string{"2-3", "2-4", "1"}, deps)
+
+	deps, err = parseDependencies(q1)
+	assert.Nil(err)
+	assert.Equal(0, len(deps))
+
+	_, err = parseDependencies(dependentMigration("3", "-- migrator:depends"))
+	assert.ErrorContains(err, "missing dependency versions at line 1 for migration '3-table-3'")
+}
+
+func TestOrderMigrations(t *testing.T) {
+	assert := assert.New(t)
+	ordered, err := orderMigrations([]types.Migration{
+		dependentMigration("3", ""),
+		dependentMigration("1", ""),
+		dependentMigration("2", ""),
+	}, nil, DEFAULT_VERSION_COMPARATOR)
+	assert.Nil(err)
+	assert.Equal([]string{"1", "2", "3"}, versions(ordered))
+
+	// Branches merged without renumbering, 2-1 was written after 3
+	ordered, err = orderMigrations([]types.Migration{
+		dependentMigration("1", ""),
+		dependentMigration("2-1", "-- migrator:depends 3"),
+		dependentMigration("2", ""),
+		dependentMigration("3", "-- migrator:depends 1"),
+		dependentMigration("4", ""),
+	}, nil, DEFAULT_VERSION_COMPARATOR)
+	assert.Nil(err)
+	assert.Equal([]string{"1", "2", "3", "2-1", "4"}, versions(ordered))
+
+	ordered, err = orderMigrations([]types.Migration{dependentMigration("2", "-- migrator:depends 1")}, map[string]bool{"1": true}, DEFAULT_VERSION_COMPARATOR)
+	assert.Nil(err)
+	assert.Equal([]string{"2"}, versions(ordered))
+}
+
+func TestOrderMigrationsErrors(t *testing.T) {
+	assert := assert.New(t)
+	_, err := orderMigrations([]types.Migration{
+		dependentMigration("1", "-- migrator:depends 3"),
+		dependentMigration("2", ""),
+		dependentMigration("3", "-- migrator:depends 1"),
+		dependentMigration("4", "-- migrator:depends 3"),
+	}, nil, DEFAULT_VERSION_COMPARATOR)
+	assert.ErrorContains(err, "found dependency cycle, following versions cannot be ordered: 1, 3, 4")
+
+	_, err = orderMigrations([]types.Migration{dependentMigration("1", "-- migrator:depends 1")}, nil, DEFAULT_VERSION_COMPARATOR)
+	assert.ErrorContains(err, "found dependency cycle, following versions cannot be ordered: 1")
+
+	_, err = orderMigrations([]types.Migration{
+		dependentMigration("2", "-- migrator:depends 1-5"),
+		dependentMigration("3", "-- migrator:depends"),
+	}, nil, DEFAULT_VERSION_COMPARATOR)
+	assert.ErrorContains(err, "migration '2-table-2' depends on version 1-5, which is not found")
+	assert.ErrorContains(err, "missing dependency versions at line 1 for migration '3-table-3'")
+}
+
+func TestMigrateWithDependencies(t *testing.T) {
+	assert := assert.New(t)
+	setup()
+	defer tearDown()
+	m1 := dependentMigration("1", "")
+	m2 := dependentMigration("2", "")
+	m3 := dependentMigration("3", "-- migrator:depends 1")
+	m21 := dependentMigration("2-1", "-- migrator:depends 3")
+
+	assert.Nil(mRun.Migrate([]types.Migration{m1, m2, m3}))
+	assert.Nil(mRun.Migrate([]types.Migration{m21}))
+	mLogs, _ := mRun.GetMigrationLogs()
+	assert.Equal(4, len(mLogs))
+
+	err := mRun.Migrate([]types.Migration{dependentMigration("5", "-- migrator:depends 4")})
+	assert.ErrorIs(err, ErrValidation)
+	assert.ErrorContains(err, "migration '5-table-5' depends on version 4, which is not found")
+
+	// Dependents are rolled back first
+	err = mRun.Rollback("3")
+	assert.ErrorIs(err, ErrValidation)
+	assert.ErrorContains(err, "cannot rollback version 3, migration '2-1-table-2-1' depends on it")
+	assert.Nil(mRun.Rollback("2-1"))
+	mLogs, _ = mRun.GetMigrationLogs()
+	assert.Equal([]string{"1", "2"}, lo.Map(mLogs, func(mLog types.MigrationLog, _ int) string {
+		return mLog.Version
+	}))
+}
+
+func TestOrderRollback(t *testing.T) {
+	assert := assert.New(t)
+	mLogs := lo.Map([]types.Migration{
+		dependentMigration("1", ""),
+		dependentMigration("2", "-- migrator:depends 3"),
+		dependentMigration("3", ""),
+		dependentMigration("4", ""),
+	}, func(m types.Migration, i int) types.MigrationLog {
+		return types.MigrationLog{Id: i + 1, Migration: m}
+	})
+	ordered, err := orderRollback("2", mLogs, DEFAULT_VERSION_COMPARATOR)
+	assert.Nil(err)
+	assert.Equal([]string{"4", "2", "3"}, lo.Map(ordered, func(mLog types.MigrationLog, _ int) string {
+		return mLog.Version
+	}))
+}
+
+func TestOrderWithComparator(t *testing.T) {
+	assert := assert.New(t)
+	mArr := []types.Migration{
+		dependentMigration("3-10a", ""),
+		dependentMigration("3-9", ""),
+		dependentMigration("3-10", "-- migrator:depends 3-10a"),
+	}
+	ordered, err := orderMigrations(mArr, nil, semver.NaturalComparator)
+	assert.Nil(err)
+	assert.Equal([]string{"3-9", "3-10a", "3-10"}, versions(ordered))
+
+	mLogs := lo.Map(ordered, func(m types.Migration, i int) types.MigrationLog {
+		return types.MigrationLog{Id: i + 1, Migration: m}
+	})
+	rollback, err := orderRollback("3-10", mLogs, semver.NaturalComparator)
+	assert.Nil(err)
+	assert.Equal([]string{"3-10", "3-10a"}, lo.Map(rollback, func(mLog types.MigrationLog, _ int) string {
+		return mLog.Version
+	}))
+}
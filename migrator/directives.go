@@ -0,0 +1,42 @@
+package migrator
+
+import (
+	"strings"
+)
+
+const DIRECTIVE_PREFIX = "-- migrator:"
+
+// Instruction for the migrator, declared in the header of a migration query.
+// E.g. '-- migrator:require:skip SELECT COUNT(*) FROM USERS' has name 'require', mode 'skip' and the query as value
+type directive struct {
+	name  string
+	mode  string
+	value string
+	line  int
+}
+
+// Parses directives from the header of a query, i.e. the leading blank and comment lines
+func parseDirectives(query string) []directive {
+	directives := []directive{}
+	for i, line := range strings.Split(query, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		if !strings.HasPrefix(line, "--") {
+			break
+		}
+		if !strings.HasPrefix(line, DIRECTIVE_PREFIX) {
+			continue
+		}
+		nameAndMode, value, _ := strings.Cut(strings.TrimPrefix(line, DIRECTIVE_PREFIX), " ")
+		name, mode, _ := strings.Cut(nameAndMode, ":")
+		directives = append(directives, directive{
+			name:  name,
+			mode:  mode,
+			value: strings.TrimSpace(value),
+			line:  i + 1,
+		})
+	}
+	return directives
+}
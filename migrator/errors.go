@@ -0,0 +1,31 @@
+package migrator
+
+import "errors"
+
+// Kinds of migration errors, check with errors.Is
+var (
+	ErrValidation = errors.New("migration validation error")
+	ErrDrift      = errors.New("migration drift error")
+	ErrExecution  = errors.New("migration execution error")
+)
+
+// Tags err with a kind, without changing its message
+type kindError struct {
+	kind error
+	err  error
+}
+
+func withKind(kind error, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &kindError{kind: kind, err: err}
+}
+
+func (e *kindError) Error() string {
+	return e.err.Error()
+}
+
+func (e *kindError) Unwrap() []error {
+	return []error{e.kind, e.err}
+}
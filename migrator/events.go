@@ -0,0 +1,99 @@
+package migrator
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/wizards-0/go-pins/logger"
+)
+
+type EventType string
+
+const (
+	EVENT_STARTED  EventType = "started"
+	EVENT_FINISHED EventType = "finished"
+	EVENT_FAILED   EventType = "failed"
+	EVENT_SKIPPED  EventType = "skipped"
+)
+
+// Progress of a single migration. Action is one of types.HISTORY_ACTION_APPLY | types.HISTORY_ACTION_ROLLBACK.
+// Duration is zero for started events
+type Event struct {
+	Type     EventType
+	Action   string
+	Version  string
+	Name     string
+	Duration time.Duration
+	Err      error
+}
+
+// Observers are notified synchronously, from the goroutine running the migrations
+type Observer interface {
+	OnEvent(e Event)
+}
+
+type ObserverFunc func(e Event)
+
+func (f ObserverFunc) OnEvent(e Event) {
+	f(e)
+}
+
+// Default observer, logs events via logger.Info
+func NewLogObserver() Observer {
+	return ObserverFunc(func(e Event) {
+		switch e.Type {
+		case EVENT_STARTED:
+			logger.Info(fmt.Sprintf("Started %v of migration '%v-%v'", e.Action, e.Version, e.Name))
+		case EVENT_FINISHED:
+			logger.Info(fmt.Sprintf("Finished %v of migration '%v-%v' in %v", e.Action, e.Version, e.Name, e.Duration))
+		case EVENT_SKIPPED:
+			logger.Info(fmt.Sprintf("Skipped %v of migration '%v-%v' after %v", e.Action, e.Version, e.Name, e.Duration))
+		case EVENT_FAILED:
+			logger.Info(fmt.Sprintf("Failed %v of migration '%v-%v' after %v", e.Action, e.Version, e.Name, e.Duration))
+		}
+	})
+}
+
+// Forwards events to a channel, e.g. for rendering progress in a UI.
+// Sending blocks while the buffer is full, so the channel has to be drained while migrations run
+type ChannelObserver struct {
+	ch chan Event
+}
+
+func NewChannelObserver(buffer int) *ChannelObserver {
+	return &ChannelObserver{ch: make(chan Event, buffer)}
+}
+
+func (o *ChannelObserver) OnEvent(e Event) {
+	o.ch <- e
+}
+
+func (o *ChannelObserver) Events() <-chan Event {
+	return o.ch
+}
+
+// Closes the events channel. Call it after migrations are done, not while they are running
+func (o *ChannelObserver) Close() {
+	close(o.ch)
+}
+
+func (m *migrator) notify(e Event) {
+	for _, o := range m.observers {
+		o.OnEvent(e)
+	}
+}
+
+// Runs fn for a migration, notifying observers about its start and outcome
+func (m *migrator) observe(action string, version string, name string, fn func() (bool, error)) error {
+	m.notify(Event{Type: EVENT_STARTED, Action: action, Version: version, Name: name})
+	start := time.Now()
+	skipped, err := fn()
+	e := Event{Type: EVENT_FINISHED, Action: action, Version: version, Name: name, Duration: time.Since(start), Err: err}
+	if err != nil {
+		e.Type = EVENT_FAILED
+	} else if skipped {
+		e.Type = EVENT_SKIPPED
+	}
+	m.notify(e)
+	return err
+}
@@ -0,0 +1,61 @@
+package migrator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wizards-0/go-pins/migrator/types"
+)
+
+func TestChannelObserver(t *testing.T) {
+	assert := assert.New(t)
+	setup()
+	defer tearDown()
+	o := NewChannelObserver(0)
+	mRun = New(db, "", WithObservers(o))
+
+	events := []Event{}
+	done := make(chan bool)
+	go func() {
+		for e := range o.Events() {
+			events = append(events, e)
+		}
+		done <- true
+	}()
+	skipped := types.Migration{Name: "Skipped", Version: "3", Query: "-- migrator:require:skip SELECT 0", Rollback: "SELECT 1"}
+	bad := types.Migration{Name: "Bad", Version: "4", Query: "CREATE TABLE", Rollback: "SELECT 1"}
+	mRun.Migrate([]types.Migration{q1, q2, skipped, bad})
+	mRun.Rollback("2")
+	o.Close()
+	<-done
+
+	summary := []string{}
+	for _, e := range events {
+		summary = append(summary, string(e.Type)+" "+e.Action+" "+e.Version)
+	}
+	assert.Equal([]string{
+		"started apply 1", "finished apply 1",
+		"started apply 2", "finished apply 2",
+		"started apply 3", "skipped apply 3",
+		"started apply 4", "failed apply 4",
+		"started rollback 2", "finished rollback 2",
+	}, summary)
+	assert.Equal("Create test table", events[1].Name)
+	assert.NotZero(events[1].Duration)
+	assert.Zero(events[0].Duration)
+	assert.ErrorContains(events[7].Err, "error while executing query for migration '4-Bad'")
+}
+
+func TestLogObserver(t *testing.T) {
+	assert := assert.New(t)
+	setup()
+	defer tearDown()
+
+	buf.Reset()
+	mRun.Migrate([]types.Migration{q1, {Name: "Skipped", Version: "2", Query: "-- migrator:require:skip SELECT 0", Rollback: "SELECT 1"}})
+	mRun.Migrate([]types.Migration{{Name: "Bad", Version: "3", Query: "CREATE TABLE", Rollback: "SELECT 1"}})
+	assert.Contains(buf.String(), "Started apply of migration '1-Create test table'")
+	assert.Contains(buf.String(), "Finished apply of migration '1-Create test table' in ")
+	assert.Contains(buf.String(), "Skipped apply of migration '2-Skipped' after ")
+	assert.Contains(buf.String(), "Failed apply of migration '3-Bad' after ")
+}
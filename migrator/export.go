@@ -0,0 +1,159 @@
+package migrator
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/samber/lo"
+	"github.com/wizards-0/go-pins/logger"
+	"github.com/wizards-0/go-pins/migrator/types"
+	"github.com/wizards-0/go-pins/pins"
+)
+
+// Path for reading from stdin / writing to stdout, in export & import commands
+const STD_STREAM_PATH = "-"
+
+func (m *migrator) parseExportArgs(args []string) error {
+	if len(args) != 2 {
+		return errors.New("export command needs to have file path as second arg, or '-' for stdout. Example 'export migration-log.json'")
+	}
+	if args[1] == STD_STREAM_PATH {
+		return m.ExportMigrationLogs(os.Stdout)
+	}
+	f, err := os.Create(args[1])
+	if err != nil {
+		return withKind(ErrExecution, fmt.Errorf("error in creating export file %v\n%w", args[1], err))
+	}
+	if err := pins.MergeErrors(m.ExportMigrationLogs(f), f.Close()); err != nil {
+		return err
+	}
+	// Not logged for stdout, as it would be mixed with the exported json
+	logger.Info(fmt.Sprintf("Exported migration logs to %v", args[1]))
+	return nil
+}
+
+func (m *migrator) parseImportArgs(args []string) error {
+	if len(args) != 2 {
+		return errors.New("import command needs to have file path as second arg, or '-' for stdin. Example 'import migration-log.json'")
+	}
+	if args[1] == STD_STREAM_PATH {
+		return m.ImportMigrationLogs(os.Stdin)
+	}
+	f, err := os.Open(args[1])
+	if err != nil {
+		return withKind(ErrValidation, fmt.Errorf("error in opening import file %v\n%w", args[1], err))
+	}
+	defer f.Close()
+	return m.ImportMigrationLogs(f)
+}
+
+// Writes migration logs as a json array, in version order
+func (m *migrator) ExportMigrationLogs(w io.Writer) error {
+	mLogs, err := m.GetMigrationLogs()
+	if err != nil {
+		return withKind(ErrExecution, fmt.Errorf("error while exporting migration logs\n%w", err))
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(mLogs); err != nil {
+		return withKind(ErrExecution, fmt.Errorf("error while writing exported migration logs\n%w", err))
+	}
+	return nil
+}
+
+// Restores exported migration logs. Versions already present with the same hash are skipped.
+// If any version is present with a different hash, nothing is imported
+func (m *migrator) ImportMigrationLogs(r io.Reader) error {
+	imported := []types.MigrationLog{}
+	if err := json.NewDecoder(r).Decode(&imported); err != nil {
+		return withKind(ErrValidation, fmt.Errorf("error while reading migration logs to import\n%w", err))
+	}
+	if err := validateImport(imported); err != nil {
+		return withKind(ErrValidation, fmt.Errorf("error while validating migration logs to import\n%w", err))
+	}
+	slices.SortFunc(imported, func(l1, l2 types.MigrationLog) int {
+		return m.comparator.Compare(l1.Version, l2.Version)
+	})
+
+	var importErr error
+	count := 0
+	txErr := m.txProvider.WithTx(func(tx *sqlx.Tx) bool {
+		count, importErr = m.importMigrationLogs(tx, imported)
+		return importErr == nil
+	})
+	if err := pins.MergeErrors(txErr, importErr); err != nil {
+		if errors.Is(err, ErrDrift) {
+			return fmt.Errorf("error while importing migration logs\n%w", err)
+		}
+		return withKind(ErrExecution, fmt.Errorf("error while importing migration logs\n%w", err))
+	}
+	logger.Info(fmt.Sprintf("Imported %v migration logs, %v were already present", count, len(imported)-count))
+	return nil
+}
+
+func validateImport(mLogs []types.MigrationLog) error {
+	errs := []error{}
+	versions := map[string]bool{}
+	for _, mLog := range mLogs {
+		if mLog.Version == "" {
+			errs = append(errs, fmt.Errorf("missing version for migration '%v'", mLog.Name))
+			continue
+		}
+		if versions[mLog.Version] {
+			errs = append(errs, fmt.Errorf("duplicate version %v", mLog.Version))
+		}
+		versions[mLog.Version] = true
+		if mLog.Hash != hashQuery(mLog.Query) {
+			errs = append(errs, fmt.Errorf("hash does not match query for version %v", mLog.Version))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Returns the number of migration logs inserted
+func (m *migrator) importMigrationLogs(tx *sqlx.Tx, imported []types.MigrationLog) (int, error) {
+	if err := m.dao.SetupMigrationTable(tx); err != nil {
+		return 0, err
+	}
+	mLogs, err := m.dao.GetMigrationLogs(tx)
+	if err != nil {
+		return 0, err
+	}
+	mMap := lo.KeyBy(mLogs, func(mLog types.MigrationLog) string {
+		return mLog.Version
+	})
+	conflicts := []error{}
+	for _, mLog := range imported {
+		if existing, exists := mMap[mLog.Version]; exists && existing.Hash != mLog.Hash {
+			conflicts = append(conflicts, fmt.Errorf("version %v is already applied with a different query, existing '%v' imported '%v'", mLog.Version, existing.Name, mLog.Name))
+		}
+	}
+	if len(conflicts) > 0 {
+		return 0, withKind(ErrDrift, fmt.Errorf("found %v conflicting migration logs\n%w", len(conflicts), errors.Join(conflicts...)))
+	}
+
+	maxId := lo.Max(lo.Map(mLogs, func(mLog types.MigrationLog, _ int) int {
+		return mLog.Id
+	}))
+	count := 0
+	for _, mLog := range imported {
+		if _, exists := mMap[mLog.Version]; exists {
+			continue
+		}
+		maxId++
+		mLog.Id = maxId
+		if err := m.dao.InsertMigrationLog(tx, mLog); err != nil {
+			return 0, fmt.Errorf("error while importing migration log for '%v-%v'\n%w", mLog.Version, mLog.Name, err)
+		}
+		if err := m.insertMigrationHistory(tx, mLog, types.HISTORY_ACTION_IMPORT); err != nil {
+			return 0, err
+		}
+		count++
+	}
+	return count, nil
+}
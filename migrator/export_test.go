@@ -0,0 +1,153 @@
+package migrator
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/wizards-0/go-pins/logger"
+	"github.com/wizards-0/go-pins/migrator/types"
+)
+
+func getTargetDb() *sqlx.DB {
+	target := sqlx.MustOpen("sqlite3", "file:import-db?mode=memory&cache=shared")
+	target.SetMaxOpenConns(1)
+	return target
+}
+
+func TestExportImport(t *testing.T) {
+	assert := assert.New(t)
+	setup()
+	defer tearDown()
+	assert.Nil(mRun.Migrate([]types.Migration{q1, q2}))
+
+	out := bytes.Buffer{}
+	assert.Nil(mRun.ExportMigrationLogs(&out))
+	exported := []map[string]any{}
+	assert.Nil(json.Unmarshal(out.Bytes(), &exported))
+	assert.Equal(2, len(exported))
+	assert.Equal("1", exported[0]["version"])
+	assert.Equal(q1.Rollback, exported[0]["rollback"])
+
+	target := getTargetDb()
+	defer target.Close()
+	mTarget := New(target, "", WithActor("importer"))
+	assert.Nil(mTarget.Migrate([]types.Migration{q1}))
+	buf.Reset()
+	assert.Nil(mTarget.ImportMigrationLogs(bytes.NewReader(out.Bytes())))
+	assert.Contains(buf.String(), "Imported 1 migration logs, 1 were already present")
+
+	mLogs, err := mTarget.GetMigrationLogs()
+	assert.Nil(err)
+	assert.Equal(2, len(mLogs))
+	assert.Equal(2, mLogs[1].Id)
+	assert.Equal(q2.Query, mLogs[1].Query)
+	assert.Equal(int64(exported[1]["date"].(float64)), mLogs[1].Date)
+	history, err := mTarget.GetMigrationHistory("2")
+	assert.Nil(err)
+	assert.Equal(1, len(history))
+	assert.Equal(types.HISTORY_ACTION_IMPORT, history[0].Action)
+	assert.Equal("importer", history[0].Actor)
+
+	// Imported migrations are treated as applied
+	assert.Nil(mTarget.Migrate([]types.Migration{q1, q2}))
+	mLogs, _ = mTarget.GetMigrationLogs()
+	assert.Equal(2, len(mLogs))
+}
+
+func TestImportConflicts(t *testing.T) {
+	assert := assert.New(t)
+	setup()
+	defer tearDown()
+	assert.Nil(mRun.Migrate([]types.Migration{q1, q2}))
+	out := bytes.Buffer{}
+	assert.Nil(mRun.ExportMigrationLogs(&out))
+
+	target := getTargetDb()
+	defer target.Close()
+	mTarget := New(target, "")
+	assert.Nil(mTarget.Migrate([]types.Migration{modifiedQ1}))
+	err := mTarget.ImportMigrationLogs(bytes.NewReader(out.Bytes()))
+	assert.ErrorIs(err, ErrDrift)
+	assert.ErrorContains(err, "found 1 conflicting migration logs")
+	assert.ErrorContains(err, "version 1 is already applied with a different query, existing 'Create test table1' imported 'Create test table'")
+	mLogs, _ := mTarget.GetMigrationLogs()
+	assert.Equal(1, len(mLogs))
+}
+
+func TestImportValidation(t *testing.T) {
+	assert := assert.New(t)
+	setup()
+	defer tearDown()
+
+	err := mRun.ImportMigrationLogs(strings.NewReader("{"))
+	assert.ErrorIs(err, ErrValidation)
+	assert.ErrorContains(err, "error while reading migration logs to import")
+
+	mLogs := []types.MigrationLog{
+		{Migration: q1, Hash: hashQuery(q1.Query)},
+		{Migration: q1, Hash: hashQuery(q1.Query)},
+		{Migration: q2, Hash: "tampered"},
+		{Migration: types.Migration{Name: "no version"}},
+	}
+	data, _ := json.Marshal(mLogs)
+	err = mRun.ImportMigrationLogs(bytes.NewReader(data))
+	assert.ErrorIs(err, ErrValidation)
+	assert.ErrorContains(err, "duplicate version 1")
+	assert.ErrorContains(err, "hash does not match query for version 2")
+	assert.ErrorContains(err, "missing version for migration 'no version'")
+}
+
+func TestExportImportCli(t *testing.T) {
+	assert := assert.New(t)
+	setup()
+	defer tearDown()
+	assert.Nil(mRun.Migrate([]types.Migration{q1}))
+	file := filepath.Join(t.TempDir(), "migration-log.json")
+	assert.Nil(mRun.Cli([]string{"main", "export", file}))
+
+	target := getTargetDb()
+	defer target.Close()
+	mTarget := New(target, "")
+	assert.Nil(mTarget.Cli([]string{"main", "import", file}))
+	mLogs, _ := mTarget.GetMigrationLogs()
+	assert.Equal(1, len(mLogs))
+
+	err := mRun.Cli([]string{"main", "export"})
+	assert.ErrorContains(err, "export command needs to have file path as second arg")
+	err = mRun.Cli([]string{"main", "import", "a", "b"})
+	assert.ErrorContains(err, "import command needs to have file path as second arg")
+	err = mRun.Cli([]string{"main", "import", filepath.Join(t.TempDir(), "missing.json")})
+	assert.ErrorIs(err, ErrValidation)
+	err = mRun.Cli([]string{"main", "export", filepath.Join(t.TempDir(), "missing", "out.json")})
+	assert.ErrorContains(err, "error in creating export file")
+}
+
+func TestExportToStdout(t *testing.T) {
+	assert := assert.New(t)
+	setup()
+	defer tearDown()
+	assert.Nil(mRun.Migrate([]types.Migration{q1, q2}))
+
+	// Default writers log to stdout, so logs must not be mixed with the exported json
+	stdout := os.Stdout
+	out, err := os.Create(filepath.Join(t.TempDir(), "stdout.json"))
+	assert.Nil(err)
+	os.Stdout = out
+	logger.ResetWriters()
+	err = mRun.Cli([]string{"main", "export", STD_STREAM_PATH})
+	os.Stdout = stdout
+	logger.SetWriter(&buf, &buf, &buf, &buf)
+	assert.Nil(err)
+	assert.Nil(out.Close())
+
+	content, _ := os.ReadFile(out.Name())
+	mLogs := []types.MigrationLog{}
+	assert.Nil(json.Unmarshal(content, &mLogs))
+	assert.Equal(2, len(mLogs))
+}
@@ -6,7 +6,8 @@ import (
 	"encoding/base64"
 	"errors"
 	"fmt"
-	"sort"
+	"io"
+	"slices"
 	"time"
 
 	"github.com/jmoiron/sqlx"
@@ -16,37 +17,76 @@ import (
 	"github.com/wizards-0/go-pins/migrator/types"
 	"github.com/wizards-0/go-pins/pins"
 	"github.com/wizards-0/go-pins/semver"
-	"github.com/wizards-0/go-pins/slu"
 )
 
 type Migrator interface {
 	Cli(osArgs []string) error
 	GetMigrationLogs() ([]types.MigrationLog, error)
+	GetMigrationHistory(version string) ([]types.MigrationHistory, error)
+	GetStatus(path string) (types.MigrationStatus, error)
 	RunMigrationsFromDirectory(path string) error
 	Migrate(mArr []types.Migration) error
 	Rollback(ver string) error
+	RollbackFromDirectory(path string, ver string) error
+	Baseline(path string, ver string) error
+	Repair(path string) error
+	Backfill(b Backfill) error
+	ExportMigrationLogs(w io.Writer) error
+	ImportMigrationLogs(r io.Reader) error
 }
 
-func New(db *sqlx.DB, schema string) Migrator {
-	return &migrator{
-		db:  db,
-		dao: dao.NewMigrationDao(schema),
+type RollbackMode string
+
+const (
+	// Rollback drift is logged, and rollback executes the script stored in migration log
+	ROLLBACK_MODE_STORED RollbackMode = "stored"
+	// Rollback drift fails Migrate, and RollbackFromDirectory refuses to run
+	ROLLBACK_MODE_STRICT RollbackMode = "strict"
+)
+
+func New(db *sqlx.DB, schema string, opts ...Option) Migrator {
+	return newMigrator(db, dao.NewMigrationDao(schema), opts...)
+}
+
+func newMigrator(db *sqlx.DB, dao dao.MigrationDao, opts ...Option) Migrator {
+	m := &migrator{
+		db:           db,
+		dao:          dao,
+		actor:        defaultActor(),
+		retryPolicy:  NoRetry,
+		observers:    []Observer{NewLogObserver()},
+		rollbackMode: ROLLBACK_MODE_STORED,
+		sleep:        time.Sleep,
+		txProvider:   NewDbTxProvider(db),
+		comparator:   DEFAULT_VERSION_COMPARATOR,
 	}
+	return applyOptions(m, opts)
 }
 
-func newMigrator(db *sqlx.DB, dao dao.MigrationDao) Migrator {
-	return &migrator{
-		db:  db,
-		dao: dao,
+func applyOptions(m *migrator, opts []Option) *migrator {
+	for _, opt := range opts {
+		opt(m)
 	}
+	return m
 }
 
 type migrator struct {
-	db  *sqlx.DB
-	dao dao.MigrationDao
+	db            *sqlx.DB
+	dao           dao.MigrationDao
+	actor         string
+	retryPolicy   RetryPolicy
+	sleep         func(d time.Duration)
+	observers     []Observer
+	rollbackMode  RollbackMode
+	parserOptions ParserOptions
+	txProvider    TxProvider
+	comparator    semver.Comparator
 }
 
 func (m *migrator) Cli(osArgs []string) error {
+	if len(osArgs) < 2 {
+		return errors.New("missing migration command. Valid options are 'run <path>' | 'rollback <version> [path]' | 'baseline <version> <path>' | 'repair <path>' | 'history [version]' | 'export <file>' | 'import <file>'")
+	}
 	args := osArgs[1:]
 	cmd := args[0]
 	switch cmd {
@@ -54,17 +94,49 @@ func (m *migrator) Cli(osArgs []string) error {
 		return m.parseMigrationArgs(args)
 	case "rollback":
 		return m.parseRollbackArgs(args)
+	case "baseline":
+		return m.parseBaselineArgs(args)
+	case "repair":
+		return m.parseRepairArgs(args)
+	case "history":
+		return m.parseHistoryArgs(args)
+	case "export":
+		return m.parseExportArgs(args)
+	case "import":
+		return m.parseImportArgs(args)
 	default:
-		return errors.New("invalid migration command. Valid options are 'run <path>' | 'rollback <version>'")
+		return errors.New("invalid migration command. Valid options are 'run <path>' | 'rollback <version> [path]' | 'baseline <version> <path>' | 'repair <path>' | 'history [version]' | 'export <file>' | 'import <file>'")
+	}
+}
+
+func (m *migrator) parseHistoryArgs(args []string) error {
+	if len(args) > 2 {
+		return errors.New("history command takes optional version as second arg. Example 'history 1.1'")
+	}
+	version := ""
+	if len(args) == 2 {
+		version = args[1]
+	}
+	history, err := m.GetMigrationHistory(version)
+	if err != nil {
+		return err
 	}
+	logger.Info(getHistoryInfo(history))
+	return nil
 }
 
 func (m *migrator) parseRollbackArgs(args []string) error {
-	if len(args) != 2 {
-		return errors.New("rollback command needs to have version as second arg. Example 'rollback 1.1'")
+	if len(args) != 2 && len(args) != 3 {
+		return errors.New("rollback command needs to have version as second arg, and optional path as third arg. Example 'rollback 1.1 ./migrations'")
 	}
 	version := args[1]
-	if err := m.Rollback(version); err != nil {
+	var err error
+	if len(args) == 3 {
+		err = m.RollbackFromDirectory(args[2], version)
+	} else {
+		err = m.Rollback(version)
+	}
+	if err != nil {
 		return err
 	}
 	mLogs, fetchErr := m.GetMigrationLogs()
@@ -96,76 +168,187 @@ func (m *migrator) parseMigrationArgs(args []string) error {
 }
 
 func (m *migrator) GetMigrationLogs() (mArr []types.MigrationLog, err error) {
-	txErr := slu.WithDefaultCtxTx(m.db, func(tx *sqlx.Tx) bool {
+	txErr := m.txProvider.WithTx(func(tx *sqlx.Tx) bool {
 		mArr, err = m.dao.GetMigrationLogs(tx)
 		return err == nil
 	})
+	slices.SortStableFunc(mArr, func(l1, l2 types.MigrationLog) int {
+		return m.comparator.Compare(l1.Version, l2.Version)
+	})
 	return mArr, pins.MergeErrors(txErr, err)
 }
 
+func (m *migrator) GetMigrationHistory(version string) (history []types.MigrationHistory, err error) {
+	txErr := m.txProvider.WithTx(func(tx *sqlx.Tx) bool {
+		history, err = m.dao.GetMigrationHistory(tx, version)
+		return err == nil
+	})
+	return history, withKind(ErrExecution, pins.MergeErrors(txErr, err))
+}
+
+// Compares migrations in the directory with the migration log. Pending migrations are in execution order
+func (m *migrator) GetStatus(path string) (types.MigrationStatus, error) {
+	mArr, err := parseDirectoryWithOptions(path, m.parserOptions, m.comparator)
+	if err != nil {
+		return types.MigrationStatus{}, withKind(ErrValidation, fmt.Errorf("error while getting migration status for path %v\n%w", path, err))
+	}
+	mLogs, fetchErr := m.GetMigrationLogs()
+	if fetchErr != nil {
+		return types.MigrationStatus{}, withKind(ErrExecution, fmt.Errorf("error while getting migration status\n%w", fetchErr))
+	}
+	mMap := lo.KeyBy(mLogs, func(mLog types.MigrationLog) string {
+		return mLog.Version
+	})
+	status := types.MigrationStatus{
+		Applied:         mLogs,
+		Pending:         []types.Migration{},
+		QueryDrifted:    []types.MigrationLog{},
+		RollbackDrifted: []types.MigrationLog{},
+	}
+	for _, mig := range mArr {
+		if mLog, exists := mMap[mig.Version]; exists {
+			if validateHash(mLog, hashQuery(mig.Query)) != nil {
+				status.QueryDrifted = append(status.QueryDrifted, mLog)
+			}
+			if validateRollback(mLog, mig) != nil {
+				status.RollbackDrifted = append(status.RollbackDrifted, mLog)
+			}
+		} else {
+			status.Pending = append(status.Pending, mig)
+		}
+	}
+	applied := lo.MapValues(mMap, func(_ types.MigrationLog, _ string) bool {
+		return true
+	})
+	pending, orderErr := orderMigrations(status.Pending, applied, m.comparator)
+	if orderErr != nil {
+		return types.MigrationStatus{}, withKind(ErrValidation, fmt.Errorf("error while ordering pending migrations by dependencies\n%w", orderErr))
+	}
+	status.Pending = pending
+	return status, nil
+}
+
 func (m *migrator) RunMigrationsFromDirectory(path string) error {
-	mArr, err := parseDirectory(path)
+	mArr, err := parseDirectoryWithOptions(path, m.parserOptions, m.comparator)
 	if err != nil {
-		return fmt.Errorf("error while running migrations from path %v\n%w", path, err)
+		return withKind(ErrValidation, fmt.Errorf("error while running migrations from path %v\n%w", path, err))
 	}
 	return m.Migrate(mArr)
 }
 
 func (m *migrator) Migrate(mArr []types.Migration) error {
 	var setupErr error
-	txErr := slu.WithDefaultCtxTx(m.db, func(tx *sqlx.Tx) bool {
+	txErr := m.txProvider.WithTx(func(tx *sqlx.Tx) bool {
 		setupErr = m.dao.SetupMigrationTable(tx)
 		return setupErr == nil
 	})
 	err := pins.MergeErrors(txErr, setupErr)
 	if err != nil {
-		return fmt.Errorf("error while running migrations\n%w", err)
+		return withKind(ErrExecution, fmt.Errorf("error while running migrations\n%w", err))
 	}
 	return m.executeMigrationQueries(mArr)
 }
 
+// Rolls back migrations with version >= ver, using rollback scripts stored in migration log
 func (m *migrator) Rollback(ver string) error {
+	return m.rollback(ver, nil)
+}
+
+// Same as Rollback, but first compares stored rollback scripts with the ones in the directory
+func (m *migrator) RollbackFromDirectory(path string, ver string) error {
+	mArr, err := parseDirectoryWithOptions(path, m.parserOptions, m.comparator)
+	if err != nil {
+		return withKind(ErrValidation, fmt.Errorf("error while running rollback from path %v\n%w", path, err))
+	}
+	return m.rollback(ver, mArr)
+}
+
+func (m *migrator) rollback(ver string, mArr []types.Migration) error {
 	mLogs, fetchErr := m.GetMigrationLogs()
 	if fetchErr != nil {
-		return logger.WrapAndLogError(fetchErr, "error in executing rollback")
+		return withKind(ErrExecution, logger.WrapAndLogError(fetchErr, "error in executing rollback"))
+	}
+	mLogs, orderErr := orderRollback(ver, mLogs, m.comparator)
+	if orderErr != nil {
+		return withKind(ErrValidation, logger.LogError(fmt.Errorf("error while ordering rollback by dependencies\n%w", orderErr)))
+	}
+	if err := m.checkRollbackDrift(mLogs, mArr); err != nil {
+		return err
 	}
-	sort.Slice(mLogs, func(i1, i2 int) bool {
-		return !semver.CompareSemver(mLogs[i1].Version, mLogs[i2].Version, types.VERSION_SEPARATOR)
-	})
 	for _, mLog := range mLogs {
-		if !semver.CompareSemver(ver, mLog.Version, types.VERSION_SEPARATOR) {
-			return nil
+		err := m.observe(types.HISTORY_ACTION_ROLLBACK, mLog.Version, mLog.Name, func() (bool, error) {
+			return false, m.withRetry("rollback of '"+mLog.Version+"-"+mLog.Name+"'", func() error {
+				return m.rollbackMigration(ver, mLog)
+			})
+		})
+		if err != nil {
+			return withKind(ErrExecution, err)
 		}
+	}
+	return nil
+}
 
-		var rollbackErr error
-		txErr := slu.WithDefaultCtxTx(m.db, func(tx *sqlx.Tx) bool {
-			if err := m.dao.ExecuteRollback(tx, mLog.Migration); err != nil {
-				rollbackErr = fmt.Errorf("error while executing rollback query for version '%v'\n%w", ver, err)
-				return false
-			}
+func (m *migrator) rollbackMigration(ver string, mLog types.MigrationLog) error {
+	var rollbackErr error
+	txErr := m.txProvider.WithTx(func(tx *sqlx.Tx) bool {
+		if err := m.dao.ExecuteRollback(tx, mLog.Migration); err != nil {
+			rollbackErr = fmt.Errorf("error while executing rollback query for version '%v'\n%w", ver, err)
+			return false
+		}
 
-			if err := m.dao.DeleteMigrationLog(tx, mLog); err != nil {
-				rollbackErr = fmt.Errorf("error while deleting migration log\n%w", err)
-				return false
-			}
-			return true
-		})
-		err := pins.MergeErrors(txErr, rollbackErr)
-		if err != nil {
-			return err
+		if err := m.dao.DeleteMigrationLog(tx, mLog); err != nil {
+			rollbackErr = fmt.Errorf("error while deleting migration log\n%w", err)
+			return false
+		}
+
+		if err := m.insertMigrationHistory(tx, mLog, types.HISTORY_ACTION_ROLLBACK); err != nil {
+			rollbackErr = err
+			return false
+		}
+		return true
+	})
+	return pins.MergeErrors(txErr, rollbackErr)
+}
+
+// Checks rollback scripts on disk, of all migrations to be rolled back, before any of them is executed
+func (m *migrator) checkRollbackDrift(mLogs []types.MigrationLog, mArr []types.Migration) error {
+	mMap := lo.KeyBy(mArr, func(mig types.Migration) string {
+		return mig.Version
+	})
+	for _, mLog := range mLogs {
+		mig, exists := mMap[mLog.Version]
+		if !exists {
+			continue
+		}
+		if err := m.handleRollbackDrift(mLog, mig); err != nil {
+			return withKind(ErrDrift, fmt.Errorf("error in rollback while validating rollback script for '%v-%v'\n%w", mLog.Version, mLog.Name, err))
 		}
 	}
 	return nil
 }
 
+// Returns error for rollback drift in strict mode, logs it otherwise
+func (m *migrator) handleRollbackDrift(mLog types.MigrationLog, mig types.Migration) error {
+	err := validateRollback(mLog, mig)
+	if err == nil || m.rollbackMode == ROLLBACK_MODE_STRICT {
+		return err
+	}
+	logger.Info(fmt.Sprintf("Rollback script for version %v differs from the one stored in migration log. Stored script will be used for rollback", mLog.Version))
+	return nil
+}
+
 func (migrator migrator) executeMigrationQueries(mArr []types.Migration) error {
 	mMap, fetchErr := migrator.getMigrationVersionMap()
 	if fetchErr != nil {
-		return fmt.Errorf("error while executing migration queries\n%w", fetchErr)
+		return withKind(ErrExecution, fmt.Errorf("error while executing migration queries\n%w", fetchErr))
 	}
-	sort.Slice(mArr, func(i1, i2 int) bool {
-		return semver.CompareSemver(mArr[i1].Version, mArr[i2].Version, types.VERSION_SEPARATOR)
+	applied := lo.MapValues(mMap, func(_ types.MigrationLog, _ string) bool {
+		return true
 	})
+	mArr, orderErr := orderMigrations(mArr, applied, migrator.comparator)
+	if orderErr != nil {
+		return withKind(ErrValidation, logger.LogError(fmt.Errorf("error while ordering migrations by dependencies\n%w", orderErr)))
+	}
 	maxId := lo.MaxBy(lo.Values(mMap), func(mLog types.MigrationLog, maxLog types.MigrationLog) bool {
 		return mLog.Id > maxLog.Id
 	}).Id
@@ -173,7 +356,10 @@ func (migrator migrator) executeMigrationQueries(mArr []types.Migration) error {
 		hash := hashQuery(m.Query)
 		if mLog, exists := mMap[m.Version]; exists {
 			if hashErr := validateHash(mLog, hash); hashErr != nil {
-				return fmt.Errorf("error in execution while validating hash for '%v-%v'\n%w", mLog.Version, mLog.Name, hashErr)
+				return withKind(ErrDrift, fmt.Errorf("error in execution while validating hash for '%v-%v'\n%w", mLog.Version, mLog.Name, hashErr))
+			}
+			if rollbackErr := migrator.handleRollbackDrift(mLog, m); rollbackErr != nil {
+				return withKind(ErrDrift, fmt.Errorf("error in execution while validating rollback script for '%v-%v'\n%w", mLog.Version, mLog.Name, rollbackErr))
 			}
 		} else {
 			maxId = maxId + 1
@@ -186,8 +372,31 @@ func (migrator migrator) executeMigrationQueries(mArr []types.Migration) error {
 }
 
 func (migrator migrator) executeQuery(m types.Migration, id int, hash string) error {
+	err := migrator.observe(types.HISTORY_ACTION_APPLY, m.Version, m.Name, func() (skipped bool, err error) {
+		err = migrator.withRetry("migration '"+m.Version+"-"+m.Name+"'", func() error {
+			skipped, err = migrator.executeQueryTx(m, id, hash)
+			return err
+		})
+		return skipped, err
+	})
+	return withKind(ErrExecution, err)
+}
+
+// Returns true, if the migration was skipped due to a precondition
+func (migrator migrator) executeQueryTx(m types.Migration, id int, hash string) (bool, error) {
 	var execErr error
-	txErr := slu.WithDefaultCtxTx(migrator.db, func(tx *sqlx.Tx) bool {
+	skipped := false
+	txErr := migrator.txProvider.WithTx(func(tx *sqlx.Tx) bool {
+		skip, conditionErr := migrator.checkPreconditions(tx, m)
+		if conditionErr != nil {
+			execErr = logger.LogError(conditionErr)
+			return false
+		}
+		if skip {
+			skipped = true
+			execErr = migrator.insertSkipHistory(tx, m, hash)
+			return execErr == nil
+		}
 		if err := migrator.dao.ExecuteQuery(tx, m); err != nil {
 			execErr = logger.LogError(fmt.Errorf("error while executing query for migration '%v-%v'\n%w", m.Version, m.Name, err))
 			return false
@@ -197,14 +406,34 @@ func (migrator migrator) executeQuery(m types.Migration, id int, hash string) er
 			execErr = logger.LogError(fmt.Errorf("error while inserting migration log for migration '%v-%v'\n%w", mLog.Version, mLog.Name, insertErr))
 			return false
 		}
+		if historyErr := migrator.insertMigrationHistory(tx, mLog, types.HISTORY_ACTION_APPLY); historyErr != nil {
+			execErr = historyErr
+			return false
+		}
 		return true
 	})
 
-	return pins.MergeErrors(txErr, execErr)
+	return skipped, pins.MergeErrors(txErr, execErr)
+}
+
+// Skipped migrations are evaluated again on every run, so a skip is recorded only if it is not already
+// the latest history entry for the version and hash
+func (migrator migrator) insertSkipHistory(tx *sqlx.Tx, m types.Migration, hash string) error {
+	history, err := migrator.dao.GetMigrationHistory(tx, m.Version)
+	if err != nil {
+		return fmt.Errorf("error while getting migration history for '%v-%v'\n%w", m.Version, m.Name, err)
+	}
+	if len(history) > 0 {
+		latest := history[len(history)-1]
+		if latest.Action == types.HISTORY_ACTION_SKIP && latest.Hash == hash {
+			return nil
+		}
+	}
+	return migrator.insertMigrationHistory(tx, types.MigrationLog{Migration: m, Hash: hash}, types.HISTORY_ACTION_SKIP)
 }
 
 func (m *migrator) getMigrationVersionMap() (mMap map[string]types.MigrationLog, err error) {
-	txErr := slu.WithDefaultCtxTx(m.db, func(tx *sqlx.Tx) bool {
+	txErr := m.txProvider.WithTx(func(tx *sqlx.Tx) bool {
 		mLogs, fetchErr := m.dao.GetMigrationLogs(tx)
 		if fetchErr != nil {
 			err = fmt.Errorf("error while getting version migrations map\n %w", fetchErr)
@@ -235,6 +464,14 @@ func validateHash(m types.MigrationLog, hash string) error {
 	return nil
 }
 
+func validateRollback(mLog types.MigrationLog, m types.Migration) error {
+	if hashQuery(mLog.Rollback) != hashQuery(m.Rollback) {
+		return fmt.Errorf("DB Migration rollback checksum failed for version %v, "+
+			"rollback script differs from the one stored in migration_log table", mLog.Version)
+	}
+	return nil
+}
+
 func (m *migrator) insertMigrationLog(tx *sqlx.Tx, q types.Migration, id int, hash string) (types.MigrationLog, error) {
 	mLog := types.MigrationLog{}
 	mLog.Id = id
@@ -248,6 +485,21 @@ func (m *migrator) insertMigrationLog(tx *sqlx.Tx, q types.Migration, id int, ha
 	return mLog, nil
 }
 
+func (m *migrator) insertMigrationHistory(tx *sqlx.Tx, mLog types.MigrationLog, action string) error {
+	h := types.MigrationHistory{
+		Version: mLog.Version,
+		Name:    mLog.Name,
+		Action:  action,
+		Actor:   m.actor,
+		Date:    time.Now().UnixMilli(),
+		Hash:    mLog.Hash,
+	}
+	if err := m.dao.InsertMigrationHistory(tx, h); err != nil {
+		return fmt.Errorf("error while inserting migration history for '%v-%v'\n%w", mLog.Version, mLog.Name, err)
+	}
+	return nil
+}
+
 func getMigrationInfo(mLogs []types.MigrationLog) string {
 	buf := bytes.Buffer{}
 	buf.WriteString("\n")
@@ -280,3 +532,34 @@ func writePadded(buf *bytes.Buffer, s string, length int) {
 		buf.WriteRune(' ')
 	}
 }
+
+func getHistoryInfo(history []types.MigrationHistory) string {
+	buf := bytes.Buffer{}
+	buf.WriteString("\n")
+	for range 100 {
+		buf.WriteRune('-')
+	}
+	buf.WriteString("\n")
+	writePadded(&buf, "|  Version", 12)
+	writePadded(&buf, "|  Action", 12)
+	writePadded(&buf, "|  Actor", 20)
+	writePadded(&buf, "|  Date", 25)
+	writePadded(&buf, "|  Name", 100-69-1)
+	buf.WriteString("|\n")
+	for range 100 {
+		buf.WriteRune('-')
+	}
+	buf.WriteString("\n")
+	for _, h := range history {
+		writePadded(&buf, "|  "+h.Version, 12)
+		writePadded(&buf, "|  "+h.Action, 12)
+		writePadded(&buf, "|  "+h.Actor, 20)
+		writePadded(&buf, "|  "+time.UnixMilli(h.Date).Format(time.DateTime), 25)
+		writePadded(&buf, "|  "+h.Name, 100-69-1)
+		buf.WriteString("|\n")
+	}
+	for range 100 {
+		buf.WriteRune('-')
+	}
+	return buf.String()
+}
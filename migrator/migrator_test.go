@@ -8,12 +8,14 @@ import (
 	"testing"
 
 	"github.com/jmoiron/sqlx"
+	"github.com/samber/lo"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/wizards-0/go-pins/logger"
 	"github.com/wizards-0/go-pins/migrator/dao"
 	"github.com/wizards-0/go-pins/migrator/types"
 	mocks "github.com/wizards-0/go-pins/mocks/migrator/dao"
+	"github.com/wizards-0/go-pins/semver"
 	"github.com/wizards-0/go-pins/slu"
 )
 
@@ -112,6 +114,7 @@ func TestInvalidHashDbError(t *testing.T) {
 	// Causing hash to differ, throwing checksum error
 	err := mRun.Migrate([]types.Migration{modifiedQ1, q2})
 	assert.ErrorContains(t, err, "DB Migration checksum failed")
+	assert.ErrorIs(t, err, ErrDrift)
 }
 
 func TestRollback(t *testing.T) {
@@ -232,6 +235,164 @@ func TestRollbackDeleteLogError(t *testing.T) {
 	assert.ErrorContains(err, "error while deleting migration log")
 }
 
+func TestMigrationHistory(t *testing.T) {
+	assert := assert.New(t)
+	setup()
+	defer tearDown()
+	mRun = New(db, "", WithActor("test-user"))
+
+	mRun.Migrate([]types.Migration{q1, q2})
+	mRun.Rollback("2")
+	mRun.Migrate([]types.Migration{q1, q2})
+
+	history, err := mRun.GetMigrationHistory("")
+	assert.Nil(err)
+	assert.Equal(4, len(history))
+	assert.Equal([]string{"1", "2", "2", "2"}, []string{history[0].Version, history[1].Version, history[2].Version, history[3].Version})
+	assert.Equal(types.HISTORY_ACTION_APPLY, history[1].Action)
+	assert.Equal(types.HISTORY_ACTION_ROLLBACK, history[2].Action)
+	assert.Equal("test-user", history[2].Actor)
+	assert.Equal(hashQuery(q2.Query), history[2].Hash)
+	assert.NotZero(history[2].Date)
+
+	history, err = mRun.GetMigrationHistory("1")
+	assert.Nil(err)
+	assert.Equal(1, len(history))
+}
+
+func TestMigrationHistoryErrors(t *testing.T) {
+	assert := assert.New(t)
+	setup()
+	defer tearDown()
+	mRun.Migrate([]types.Migration{q1})
+	mockDao := mocks.NewMockMigrationDao(mDao, t)
+	mRun = newMigrator(db, mockDao)
+
+	mockDao.PassThrough("ExecuteQuery", "InsertMigrationLog")
+	mockDao.EXPECT().InsertMigrationHistory(TYPE_TX, mock.Anything).Return(errors.New("history error")).Once()
+	err := mRun.(*migrator).executeQuery(q2, 2, hashQuery(q2.Query))
+	assert.ErrorContains(err, "error while inserting migration history for '2-Create test table2'")
+
+	mockDao.PassThrough("GetMigrationLogs", "ExecuteRollback", "DeleteMigrationLog")
+	mockDao.EXPECT().InsertMigrationHistory(TYPE_TX, mock.Anything).Return(errors.New("history error")).Once()
+	err = mRun.Rollback("0")
+	assert.ErrorContains(err, "error while inserting migration history for '1-Create test table'")
+
+	mockDao.EXPECT().GetMigrationHistory(TYPE_TX, "").Return(nil, errors.New("fetch error"))
+	_, err = mRun.GetMigrationHistory("")
+	assert.ErrorContains(err, "fetch error")
+	assert.ErrorIs(err, ErrExecution)
+}
+
+func TestHistoryArgs(t *testing.T) {
+	assert := assert.New(t)
+	setup()
+	defer tearDown()
+	mRun.Migrate([]types.Migration{q1})
+
+	buf.Reset()
+	err := mRun.Cli([]string{"main", "history"})
+	assert.Nil(err)
+	assert.Contains(buf.String(), "|  1        |  apply    |  ")
+
+	err = mRun.Cli([]string{"main", "history", "2"})
+	assert.Nil(err)
+
+	err = mRun.Cli([]string{"main", "history", "1", "2"})
+	assert.ErrorContains(err, "history command takes optional version as second arg")
+
+	db.Close()
+	err = mRun.Cli([]string{"main", "history"})
+	assert.NotNil(err)
+}
+
+func TestRollbackDrift(t *testing.T) {
+	assert := assert.New(t)
+	setup()
+	defer tearDown()
+	editedQ1 := q1
+	editedQ1.Rollback = "DROP TABLE TEST; DROP TABLE OTHER;"
+
+	mRun.Migrate([]types.Migration{q1, q2})
+	buf.Reset()
+	err := mRun.Migrate([]types.Migration{editedQ1, q2})
+	assert.Nil(err)
+	assert.Contains(buf.String(), "Rollback script for version 1 differs from the one stored in migration log")
+
+	strictRun := New(db, "", WithRollbackMode(ROLLBACK_MODE_STRICT))
+	err = strictRun.Migrate([]types.Migration{editedQ1, q2})
+	assert.ErrorContains(err, "error in execution while validating rollback script for '1-Create test table'")
+	assert.ErrorContains(err, "DB Migration rollback checksum failed for version 1")
+	assert.ErrorIs(err, ErrDrift)
+}
+
+func TestRollbackFromDirectory(t *testing.T) {
+	assert := assert.New(t)
+	setup()
+	defer tearDown()
+	validQ1 := types.Migration{Name: "user-setup", Version: "1",
+		Query:    "CREATE TABLE USER_MASTER (\n    ID INTEGER PRIMARY KEY,\n    NAME VARCHAR(200)\n);",
+		Rollback: "DROP TABLE USER_MASTER; DROP TABLE NO_TABLE;",
+	}
+	mRun.Migrate([]types.Migration{validQ1})
+
+	strictRun := New(db, "", WithRollbackMode(ROLLBACK_MODE_STRICT))
+	err := strictRun.RollbackFromDirectory(VALID_PATH, "1")
+	assert.ErrorContains(err, "error in rollback while validating rollback script for '1-user-setup'")
+	assert.ErrorIs(err, ErrDrift)
+	mLogs, _ := mRun.GetMigrationLogs()
+	assert.Equal(1, len(mLogs))
+
+	// Stored rollback is used, so the rollback fails on the missing table
+	err = mRun.RollbackFromDirectory(VALID_PATH, "1")
+	assert.ErrorContains(err, "no such table: NO_TABLE")
+
+	err = mRun.RollbackFromDirectory("../invalid-path", "1")
+	assert.ErrorIs(err, ErrValidation)
+}
+
+func TestRollbackArgsWithPath(t *testing.T) {
+	assert := assert.New(t)
+	setup()
+	defer tearDown()
+
+	err := mRun.Cli([]string{"main", "run", VALID_PATH})
+	assert.Nil(err)
+	err = mRun.Cli([]string{"main", "rollback", "1", VALID_PATH})
+	assert.Nil(err)
+	mLogs, _ := mRun.GetMigrationLogs()
+	assert.Equal(0, len(mLogs))
+
+	err = mRun.Cli([]string{"main", "rollback", "1", VALID_PATH, "extra"})
+	assert.ErrorContains(err, "rollback command needs to have version as second arg")
+}
+
+func TestGetStatus(t *testing.T) {
+	assert := assert.New(t)
+	setup()
+	defer tearDown()
+	mRun.Migrate([]types.Migration{})
+
+	status, err := mRun.GetStatus("../resources/test/migrations/valid-multi-level")
+	assert.Nil(err)
+	assert.Equal(0, len(status.Applied))
+	assert.Equal([]string{"1", "2"}, []string{status.Pending[0].Version, status.Pending[1].Version})
+
+	mRun.Migrate([]types.Migration{modifiedQ1})
+	status, err = mRun.GetStatus("../resources/test/migrations/valid-multi-level")
+	assert.Nil(err)
+	assert.Equal(1, len(status.Applied))
+	assert.Equal(1, len(status.Pending))
+	assert.Equal("1", status.QueryDrifted[0].Version)
+	assert.Equal("1", status.RollbackDrifted[0].Version)
+
+	_, err = mRun.GetStatus("../invalid-path")
+	assert.ErrorIs(err, ErrValidation)
+	db.Close()
+	_, err = mRun.GetStatus(VALID_PATH)
+	assert.ErrorContains(err, "error while getting migration status")
+}
+
 func TestValidMigrationArgs(t *testing.T) {
 	assert := assert.New(t)
 	setup()
@@ -270,7 +431,7 @@ func TestParseMigrationArgsFetchError(t *testing.T) {
 		"GetMigrationLogs",
 		"InsertMigrationLog",
 		"ExecuteQuery",
-		"UpdateMigrationStatus",
+		"InsertMigrationHistory",
 	)
 
 	mockDao.EXPECT().GetMigrationLogs(TYPE_TX).Return(nil, errors.New("")).Once()
@@ -324,6 +485,9 @@ func TestInValidCmdArgs(t *testing.T) {
 
 	err := mRun.Cli([]string{"main", "bad-cmd"})
 	assert.ErrorContains(err, "invalid migration command")
+
+	err = mRun.Cli([]string{"main"})
+	assert.ErrorContains(err, "missing migration command")
 }
 
 func TestParseRollbackArgsFetchError(t *testing.T) {
@@ -374,3 +538,23 @@ var q1_1 = types.Migration{Name: "Add Column", Version: "1-1",
 	Query:    "ALTER TABLE TEST ADD COLUMN DESCRIPTION VARCHAR(2000)",
 	Rollback: "ALTER TABLE TEST DROP COLUMN DESCRIPTION",
 }
+
+func TestVersionComparator(t *testing.T) {
+	assert := assert.New(t)
+	setup()
+	defer tearDown()
+	mRun = New(db, "", WithVersionComparator(semver.NaturalComparator))
+
+	err := mRun.Migrate([]types.Migration{dependentMigration("3-10a", ""), dependentMigration("3-9", ""), dependentMigration("3-10", "")})
+	assert.Nil(err)
+	mLogs, _ := mRun.GetMigrationLogs()
+	assert.Equal([]string{"3-9", "3-10", "3-10a"}, lo.Map(mLogs, func(mLog types.MigrationLog, _ int) string {
+		return mLog.Version
+	}))
+
+	err = mRun.Rollback("3-10")
+	assert.Nil(err)
+	mLogs, _ = mRun.GetMigrationLogs()
+	assert.Equal(1, len(mLogs))
+	assert.Equal("3-9", mLogs[0].Version)
+}
@@ -0,0 +1,169 @@
+package migrator
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/wizards-0/go-pins/migrator/types"
+	"github.com/wizards-0/go-pins/pins"
+	"github.com/wizards-0/go-pins/slu"
+)
+
+const (
+	SCHEMA_STATUS_SUCCESS = "success"
+	SCHEMA_STATUS_FAILED  = "failed"
+	SCHEMA_STATUS_SKIPPED = "skipped"
+)
+
+// Replaced by the schema name in queries & rollbacks of migrations run by MigrateSchemas, e.g. CREATE TABLE ${schema}.ORDERS(Id int)
+const SCHEMA_PLACEHOLDER = "${schema}"
+
+var schemaPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+type SchemaRunConfig struct {
+	// Max no. of schemas migrated concurrently. Defaults to 1
+	Parallelism int
+	// If false, schemas which have not started yet are skipped after the first failure
+	ContinueOnError bool
+	// Query run at the start of each transaction of a schema, with %v replaced by the schema name,
+	// e.g. 'SET search_path TO %v' for postgres or 'USE %v' for mysql. Unqualified tables in migrations then refer to the schema
+	ScopeQuery string
+}
+
+type SchemaResult struct {
+	Schema   string
+	Status   string
+	Duration time.Duration
+	Err      error
+}
+
+// Fetches schema names with a query returning a single column, e.g. SELECT name FROM tenants
+func GetSchemas(db *sqlx.DB, query string, args ...any) (schemas []string, err error) {
+	txErr := slu.WithDefaultCtxTx(db, func(tx *sqlx.Tx) bool {
+		err = tx.Select(&schemas, query, args...)
+		return err == nil
+	})
+	if err = pins.MergeErrors(txErr, err); err != nil {
+		return nil, withKind(ErrExecution, fmt.Errorf("error while fetching schemas\n%w", err))
+	}
+	return schemas, nil
+}
+
+func MigrateSchemasFromDirectory(db *sqlx.DB, schemas []string, path string, cfg SchemaRunConfig, opts ...Option) ([]SchemaResult, error) {
+	// Only parsing options are needed, which do not depend on db
+	o := applyOptions(&migrator{comparator: DEFAULT_VERSION_COMPARATOR}, opts)
+	mArr, err := parseDirectoryWithOptions(path, o.parserOptions, o.comparator)
+	if err != nil {
+		return nil, withKind(ErrValidation, fmt.Errorf("error while running migrations from path %v\n%w", path, err))
+	}
+	return MigrateSchemas(db, schemas, mArr, cfg, opts...)
+}
+
+// Runs Migrate for each schema, with its own migration_log. Migrations are scoped to the schema by SCHEMA_PLACEHOLDER
+// and SchemaRunConfig.ScopeQuery, else they run on the default schema of the connection.
+// Schema names have to be identifiers. Returned error joins errors of all failed schemas
+func MigrateSchemas(db *sqlx.DB, schemas []string, mArr []types.Migration, cfg SchemaRunConfig, opts ...Option) ([]SchemaResult, error) {
+	for _, schema := range schemas {
+		if !schemaPattern.MatchString(schema) {
+			return nil, withKind(ErrValidation, fmt.Errorf("invalid schema name '%v'", schema))
+		}
+	}
+	parallelism := max(cfg.Parallelism, 1)
+	results := make([]SchemaResult, len(schemas))
+	sem := make(chan struct{}, parallelism)
+	failed := atomic.Bool{}
+	wg := sync.WaitGroup{}
+
+	for i, schema := range schemas {
+		sem <- struct{}{}
+		if failed.Load() && !cfg.ContinueOnError {
+			results[i] = SchemaResult{Schema: schema, Status: SCHEMA_STATUS_SKIPPED}
+			<-sem
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			start := time.Now()
+			err := New(db, schema, schemaOptions(schema, cfg, opts)...).Migrate(forSchema(schema, mArr))
+			results[i] = SchemaResult{Schema: schema, Status: SCHEMA_STATUS_SUCCESS, Duration: time.Since(start)}
+			if err != nil {
+				failed.Store(true)
+				results[i].Status = SCHEMA_STATUS_FAILED
+				results[i].Err = fmt.Errorf("error while migrating schema '%v'\n%w", schema, err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	errs := []error{}
+	for _, r := range results {
+		if r.Err != nil {
+			errs = append(errs, r.Err)
+		}
+	}
+	return results, errors.Join(errs...)
+}
+
+// Scopes transactions to the schema, wrapping the tx provider set by opts
+func schemaOptions(schema string, cfg SchemaRunConfig, opts []Option) []Option {
+	if cfg.ScopeQuery == "" {
+		return opts
+	}
+	return append(slices.Clone(opts), func(m *migrator) {
+		m.txProvider = NewScopedTxProvider(m.txProvider, fmt.Sprintf(cfg.ScopeQuery, schema))
+	})
+}
+
+// Returns a copy of migrations with SCHEMA_PLACEHOLDER replaced, as Migrate also sorts the migrations in place
+func forSchema(schema string, mArr []types.Migration) []types.Migration {
+	schemaArr := make([]types.Migration, len(mArr))
+	for i, m := range mArr {
+		m.Query = strings.ReplaceAll(m.Query, SCHEMA_PLACEHOLDER, schema)
+		m.Rollback = strings.ReplaceAll(m.Rollback, SCHEMA_PLACEHOLDER, schema)
+		schemaArr[i] = m
+	}
+	return schemaArr
+}
+
+func GetSchemaSummary(results []SchemaResult) string {
+	buf := bytes.Buffer{}
+	counts := map[string]int{}
+	buf.WriteString("\n")
+	for range 80 {
+		buf.WriteRune('-')
+	}
+	buf.WriteString("\n")
+	writePadded(&buf, "|  Schema", 30)
+	writePadded(&buf, "|  Status", 12)
+	writePadded(&buf, "|  Duration", 80-42-1)
+	buf.WriteString("|\n")
+	for range 80 {
+		buf.WriteRune('-')
+	}
+	buf.WriteString("\n")
+	for _, r := range results {
+		counts[r.Status]++
+		writePadded(&buf, "|  "+r.Schema, 30)
+		writePadded(&buf, "|  "+r.Status, 12)
+		writePadded(&buf, "|  "+r.Duration.Round(time.Millisecond).String(), 80-42-1)
+		buf.WriteString("|\n")
+	}
+	for range 80 {
+		buf.WriteRune('-')
+	}
+	buf.WriteString(fmt.Sprintf("\n%v succeeded, %v failed, %v skipped",
+		counts[SCHEMA_STATUS_SUCCESS], counts[SCHEMA_STATUS_FAILED], counts[SCHEMA_STATUS_SKIPPED]))
+	return buf.String()
+}
@@ -0,0 +1,116 @@
+package migrator
+
+import (
+	"testing"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/wizards-0/go-pins/migrator/types"
+)
+
+// Schemas in sqlite are attached databases. Attachments are per connection, hence a single connection
+func getMultiSchemaDb(schemas ...string) *sqlx.DB {
+	multiDb, err := sqlx.Open("sqlite3", "file:multi-schema-db?mode=memory&cache=shared")
+	if err != nil {
+		panic(err)
+	}
+	multiDb.SetMaxOpenConns(1)
+	for _, schema := range schemas {
+		multiDb.MustExec("ATTACH DATABASE 'file:" + schema + "?mode=memory&cache=shared' AS " + schema)
+	}
+	multiDb.MustExec("CREATE TABLE IF NOT EXISTS TENANTS(NAME TEXT)")
+	multiDb.MustExec("DELETE FROM TENANTS")
+	for _, schema := range schemas {
+		multiDb.MustExec("INSERT INTO TENANTS VALUES (?)", schema)
+	}
+	return multiDb
+}
+
+func TestMigrateSchemas(t *testing.T) {
+	assert := assert.New(t)
+	setup()
+	defer tearDown()
+	multiDb := getMultiSchemaDb("tenant_a", "tenant_b", "tenant_c", "tenant_f")
+	defer multiDb.Close()
+
+	schemas, err := GetSchemas(multiDb, "SELECT NAME FROM TENANTS WHERE NAME < ? ORDER BY NAME", "tenant_d")
+	assert.Nil(err)
+	assert.Equal([]string{"tenant_a", "tenant_b", "tenant_c"}, schemas)
+
+	results, err := MigrateSchemas(multiDb, schemas, []types.Migration{q2, q1}, SchemaRunConfig{Parallelism: 2})
+	assert.Nil(err)
+	assert.Equal(3, len(results))
+	for i, r := range results {
+		assert.Equal(schemas[i], r.Schema)
+		assert.Equal(SCHEMA_STATUS_SUCCESS, r.Status)
+		mLogs, _ := New(multiDb, r.Schema).GetMigrationLogs()
+		assert.Equal(2, len(mLogs))
+	}
+	assert.Contains(GetSchemaSummary(results), "3 succeeded, 0 failed, 0 skipped")
+
+	results, err = MigrateSchemasFromDirectory(multiDb, []string{"tenant_f"}, VALID_PATH, SchemaRunConfig{})
+	assert.Nil(err)
+	assert.Equal(SCHEMA_STATUS_SUCCESS, results[0].Status)
+
+	_, err = MigrateSchemasFromDirectory(multiDb, schemas, "../invalid-path", SchemaRunConfig{})
+	assert.ErrorIs(err, ErrValidation)
+}
+
+func TestMigrateSchemasScope(t *testing.T) {
+	assert := assert.New(t)
+	setup()
+	defer tearDown()
+	multiDb := getMultiSchemaDb("tenant_x", "tenant_y")
+	defer multiDb.Close()
+	multiDb.MustExec("CREATE TABLE IF NOT EXISTS SCOPES(NAME TEXT)")
+	multiDb.MustExec("DELETE FROM SCOPES")
+
+	orders := types.Migration{Name: "Create orders", Version: "1", Query: "CREATE TABLE ${schema}.ORDERS(Id int);", Rollback: "DROP TABLE ${schema}.ORDERS;"}
+	cfg := SchemaRunConfig{ScopeQuery: "INSERT INTO main.SCOPES VALUES ('%v')"}
+	results, err := MigrateSchemas(multiDb, []string{"tenant_x", "tenant_y"}, []types.Migration{orders}, cfg)
+	assert.Nil(err)
+	assert.Equal(2, len(results))
+	var count int
+	for _, schema := range []string{"tenant_x", "tenant_y"} {
+		assert.Nil(multiDb.Get(&count, "SELECT COUNT(*) FROM "+schema+".sqlite_master WHERE name = 'ORDERS'"))
+		assert.Equal(1, count)
+		assert.Nil(multiDb.Get(&count, "SELECT COUNT(*) FROM SCOPES WHERE NAME = ?", schema))
+		assert.Less(0, count)
+		mLogs, _ := New(multiDb, schema).GetMigrationLogs()
+		assert.Equal("CREATE TABLE "+schema+".ORDERS(Id int);", mLogs[0].Query)
+	}
+	assert.Nil(multiDb.Get(&count, "SELECT COUNT(*) FROM main.sqlite_master WHERE name = 'ORDERS'"))
+	assert.Equal(0, count)
+	assert.Equal("CREATE TABLE ${schema}.ORDERS(Id int);", orders.Query)
+
+	results, err = MigrateSchemas(multiDb, []string{"tenant_x"}, []types.Migration{orders}, SchemaRunConfig{ScopeQuery: "SELECT * FROM %v.MISSING"})
+	assert.ErrorContains(err, "error in scoping transaction with 'SELECT * FROM tenant_x.MISSING'")
+	assert.Equal(SCHEMA_STATUS_FAILED, results[0].Status)
+
+	_, err = MigrateSchemas(multiDb, []string{"tenant_x", "tenant_y; DROP TABLE TENANTS"}, []types.Migration{orders}, SchemaRunConfig{})
+	assert.ErrorIs(err, ErrValidation)
+	assert.ErrorContains(err, "invalid schema name 'tenant_y; DROP TABLE TENANTS'")
+}
+
+func TestMigrateSchemasFailures(t *testing.T) {
+	assert := assert.New(t)
+	setup()
+	defer tearDown()
+	multiDb := getMultiSchemaDb("tenant_d", "tenant_e")
+	defer multiDb.Close()
+	schemas := []string{"tenant_d", "missing_schema", "tenant_e"}
+
+	results, err := MigrateSchemas(multiDb, schemas, []types.Migration{q1}, SchemaRunConfig{})
+	assert.ErrorContains(err, "error while migrating schema 'missing_schema'")
+	assert.Equal([]string{SCHEMA_STATUS_SUCCESS, SCHEMA_STATUS_FAILED, SCHEMA_STATUS_SKIPPED},
+		[]string{results[0].Status, results[1].Status, results[2].Status})
+	assert.Contains(GetSchemaSummary(results), "1 succeeded, 1 failed, 1 skipped")
+
+	results, err = MigrateSchemas(multiDb, schemas, []types.Migration{q1}, SchemaRunConfig{ContinueOnError: true})
+	assert.ErrorIs(err, ErrExecution)
+	assert.Equal([]string{SCHEMA_STATUS_SUCCESS, SCHEMA_STATUS_FAILED, SCHEMA_STATUS_SUCCESS},
+		[]string{results[0].Status, results[1].Status, results[2].Status})
+
+	_, err = GetSchemas(multiDb, "SELECT NAME FROM NO_TABLE")
+	assert.ErrorContains(err, "error while fetching schemas")
+}
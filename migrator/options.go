@@ -0,0 +1,78 @@
+package migrator
+
+import (
+	"os/user"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/wizards-0/go-pins/migrator/types"
+	"github.com/wizards-0/go-pins/semver"
+)
+
+type Option func(m *migrator)
+
+// Orders versions with semver.CompareSemver, splitting them by types.VERSION_SEPARATOR
+var DEFAULT_VERSION_COMPARATOR = semver.SeparatorComparator(types.VERSION_SEPARATOR)
+
+// Sets the actor recorded in migration history. Defaults to the current os user
+func WithActor(actor string) Option {
+	return func(m *migrator) {
+		m.actor = actor
+	}
+}
+
+// Sets the policy for retrying migration and rollback transactions on transient errors. Defaults to NoRetry
+func WithRetryPolicy(p RetryPolicy) Option {
+	return func(m *migrator) {
+		m.retryPolicy = p
+	}
+}
+
+// Replaces the default log observer, with the given observers of migration progress
+func WithObservers(observers ...Observer) Option {
+	return func(m *migrator) {
+		m.observers = observers
+	}
+}
+
+// Sets how rollback scripts changed on disk after execution are treated. Defaults to ROLLBACK_MODE_STORED
+func WithRollbackMode(mode RollbackMode) Option {
+	return func(m *migrator) {
+		m.rollbackMode = mode
+	}
+}
+
+// Sets options for parsing migration directories
+func WithParserOptions(opts ParserOptions) Option {
+	return func(m *migrator) {
+		m.parserOptions = opts
+	}
+}
+
+// Sets the provider of transactions, in which migrations, rollbacks and migration log queries are run
+func WithTxProvider(p TxProvider) Option {
+	return func(m *migrator) {
+		m.txProvider = p
+	}
+}
+
+// Sets the order of migration versions, used for executing migrations, rolling back and listing migration logs.
+// Defaults to DEFAULT_VERSION_COMPARATOR. Use semver.NaturalComparator for versions like 3-10a,
+// or a semver.CalVerFormat for calendar versions like 26.10-1
+func WithVersionComparator(cmp semver.Comparator) Option {
+	return func(m *migrator) {
+		m.comparator = cmp
+	}
+}
+
+// Runs everything inside the caller owned transaction tx, using a savepoint per migration / rollback.
+// The migrator never commits or rolls back tx
+func WithCallerTx(tx *sqlx.Tx) Option {
+	return WithTxProvider(NewCallerTxProvider(tx, true))
+}
+
+func defaultActor() string {
+	if u, err := user.Current(); err == nil && u.Username != "" {
+		return u.Username
+	}
+	return "unknown"
+}
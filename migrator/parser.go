@@ -1,11 +1,13 @@
 package migrator
 
 import (
+	"errors"
 	"fmt"
+	"io/fs"
 	"maps"
 	"os"
+	"path/filepath"
 	"slices"
-	"sort"
 	"strings"
 
 	"github.com/wizards-0/go-pins/logger"
@@ -13,29 +15,103 @@ import (
 	"github.com/wizards-0/go-pins/semver"
 )
 
+// File in the root of migrations directory, with ignore patterns. One per line, lines starting with # are comments
+const IGNORE_FILE = ".migratorignore"
+
+// Always ignored, in addition to configured patterns
+var DEFAULT_IGNORE_PATTERNS = []string{IGNORE_FILE, ".DS_Store", "*.md"}
+
+type ParserOptions struct {
+	// Glob patterns (filepath.Match syntax), matched against the name and the path relative to migrations directory,
+	// of files and directories. E.g. 'drafts', '*.txt', 'legacy/*.sql'
+	IgnorePatterns []string
+	// Reports all unexpected files together, instead of failing on the first one
+	Strict bool
+}
+
+type dirParser struct {
+	root     string
+	patterns []string
+	strict   bool
+	fileErrs []error
+}
+
 func parseDirectory(path string) ([]types.Migration, error) {
+	return parseDirectoryWithOptions(path, ParserOptions{}, DEFAULT_VERSION_COMPARATOR)
+}
+
+func parseDirectoryWithOptions(path string, opts ParserOptions, cmp semver.Comparator) ([]types.Migration, error) {
 
 	if !strings.HasSuffix(path, "/") {
 		path = path + "/"
 	}
 	verMigrationMap := map[string]types.Migration{}
 
-	if err := addDirToMap(path, verMigrationMap); err != nil {
+	patterns, ignoreErr := readIgnorePatterns(path)
+	if ignoreErr != nil {
+		return nil, fmt.Errorf("error while processing dir with path '%v'\n%w", path, ignoreErr)
+	}
+	p := &dirParser{
+		root:     path,
+		patterns: slices.Concat(DEFAULT_IGNORE_PATTERNS, opts.IgnorePatterns, patterns),
+		strict:   opts.Strict,
+	}
+	if err := p.addDirToMap(path, verMigrationMap); err != nil {
+		return nil, fmt.Errorf("error while processing dir with path '%v'\n%w", path, err)
+	}
+	if len(p.fileErrs) > 0 {
+		err := fmt.Errorf("found %v unexpected files\n%w", len(p.fileErrs), errors.Join(p.fileErrs...))
 		return nil, fmt.Errorf("error while processing dir with path '%v'\n%w", path, err)
 	}
 
 	mArr := slices.Collect(maps.Values(verMigrationMap))
-	sort.Slice(mArr, func(i1, i2 int) bool {
-		return semver.CompareSemver(mArr[i1].Version, mArr[i2].Version, types.VERSION_SEPARATOR)
+	slices.SortFunc(mArr, func(m1, m2 types.Migration) int {
+		return cmp.Compare(m1.Version, m2.Version)
 	})
 
+	// Dependencies are ordered by Migrate, as dependencies on applied versions may not be in the directory anymore
 	if err := validateMigrations(mArr); err != nil {
 		return nil, fmt.Errorf("error while validating migrations\n%w", err)
 	}
 	return mArr, nil
 }
 
-func addDirToMap(path string, verMigrationMap map[string]types.Migration) error {
+func readIgnorePatterns(path string) ([]string, error) {
+	pBytes, err := os.ReadFile(path + IGNORE_FILE)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, logger.WrapAndLogError(err, "error in reading ignore file "+path+IGNORE_FILE)
+	}
+	patterns := []string{}
+	for _, line := range strings.Split(string(pBytes), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if _, err := filepath.Match(line, ""); err != nil {
+			return nil, fmt.Errorf("invalid pattern '%v' in ignore file %v\n%w", line, path+IGNORE_FILE, err)
+		}
+		patterns = append(patterns, line)
+	}
+	return patterns, nil
+}
+
+func (p *dirParser) isIgnored(filePath string, name string) bool {
+	relPath := strings.TrimSuffix(strings.TrimPrefix(filePath, p.root), "/")
+	for _, pattern := range p.patterns {
+		if nameMatch, _ := filepath.Match(pattern, name); nameMatch {
+			return true
+		}
+		if pathMatch, _ := filepath.Match(pattern, relPath); pathMatch {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *dirParser) addDirToMap(path string, verMigrationMap map[string]types.Migration) error {
 
 	entries, dirReadErr := os.ReadDir(path)
 	if dirReadErr != nil {
@@ -43,13 +119,28 @@ func addDirToMap(path string, verMigrationMap map[string]types.Migration) error
 	}
 
 	for _, entry := range entries {
+		entryPath := path + entry.Name()
+		if entry.Type().IsDir() {
+			entryPath = entryPath + "/"
+		}
+		if p.isIgnored(entryPath, entry.Name()) {
+			logger.Debug("ignoring " + entryPath)
+			continue
+		}
 		if entry.Type().IsDir() {
-			addDirToMap(path+entry.Name()+"/", verMigrationMap)
+			if err := p.addDirToMap(entryPath, verMigrationMap); err != nil {
+				return err
+			}
 		} else {
-			fileProcessErr := addFileToMap(path+entry.Name(), entry.Name(), verMigrationMap)
-			if fileProcessErr != nil {
-				return logger.WrapAndLogError(fileProcessErr, "error in processing file "+entry.Name())
+			fileProcessErr := addFileToMap(entryPath, entry.Name(), verMigrationMap)
+			if fileProcessErr == nil {
+				continue
+			}
+			fileProcessErr = logger.WrapAndLogError(fileProcessErr, "error in processing file "+entryPath)
+			if !p.strict {
+				return fileProcessErr
 			}
+			p.fileErrs = append(p.fileErrs, fileProcessErr)
 		}
 	}
 
@@ -121,6 +212,9 @@ func validateMigrations(mArr []types.Migration) error {
 		if len(m.Rollback) == 0 {
 			return missingRollback(m)
 		}
+		if _, err := parsePreconditions(m); err != nil {
+			return logger.LogError(err)
+		}
 	}
 	return nil
 }
@@ -1,6 +1,7 @@
 package migrator
 
 import (
+	"os"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -87,3 +88,96 @@ func TestInvalidFilePath(t *testing.T) {
 	err := addFileToMap("../invalid-path/", "invalid-file.txt", map[string]types.Migration{})
 	assert.ErrorContains(err, "The system cannot find the file specified")
 }
+
+func TestIgnorePatterns(t *testing.T) {
+	setup()
+	assert := assert.New(t)
+	migrations, err := parseDirectory("../resources/test/migrations/ignore")
+	assert.Nil(err)
+	assert.Equal(1, len(migrations))
+	assert.Equal("user-setup", migrations[0].Name)
+
+	_, err = parseDirectoryWithOptions("../resources/test/migrations/ignore-missing", ParserOptions{}, DEFAULT_VERSION_COMPARATOR)
+	assert.ErrorContains(err, "error in reading directory")
+
+	migrations, err = parseDirectoryWithOptions("../resources/test/migrations/strict-multiple", ParserOptions{IgnorePatterns: []string{"*.txt", "nested/*.sql"}}, DEFAULT_VERSION_COMPARATOR)
+	assert.Nil(err)
+	assert.Equal(1, len(migrations))
+}
+
+func TestNestedDirErrors(t *testing.T) {
+	setup()
+	assert := assert.New(t)
+	_, err := parseDirectory("../resources/test/migrations/nested-invalid")
+	assert.ErrorContains(err, "error in processing file ../resources/test/migrations/nested-invalid/level-1/level-2/1.user-setup.dml.sql")
+	assert.ErrorContains(err, invalid_filename)
+}
+
+func TestStrictParsing(t *testing.T) {
+	setup()
+	assert := assert.New(t)
+	_, err := parseDirectory("../resources/test/migrations/strict-multiple")
+	assert.ErrorContains(err, "strict-multiple/nested/2.bad-name.sql")
+	assert.NotContains(err.Error(), "notes.txt")
+
+	_, err = parseDirectoryWithOptions("../resources/test/migrations/strict-multiple", ParserOptions{Strict: true}, DEFAULT_VERSION_COMPARATOR)
+	assert.ErrorContains(err, "found 2 unexpected files")
+	assert.ErrorContains(err, "error in processing file ../resources/test/migrations/strict-multiple/nested/2.bad-name.sql")
+	assert.ErrorContains(err, "error in processing file ../resources/test/migrations/strict-multiple/notes.txt")
+}
+
+func TestInvalidIgnoreFile(t *testing.T) {
+	setup()
+	assert := assert.New(t)
+	dir := t.TempDir() + "/"
+	os.WriteFile(dir+IGNORE_FILE, []byte("[invalid"), 0644)
+	_, err := parseDirectory(dir)
+	assert.ErrorContains(err, "invalid pattern '[invalid' in ignore file")
+
+	os.Remove(dir + IGNORE_FILE)
+	os.Mkdir(dir+IGNORE_FILE, 0755)
+	_, err = parseDirectory(dir)
+	assert.ErrorContains(err, "error in reading ignore file")
+}
+
+func TestMigratorParserOptions(t *testing.T) {
+	setup()
+	defer tearDown()
+	assert := assert.New(t)
+	err := mRun.RunMigrationsFromDirectory("../resources/test/migrations/strict-multiple")
+	assert.ErrorIs(err, ErrValidation)
+
+	mRun = New(db, "", WithParserOptions(ParserOptions{IgnorePatterns: []string{"*.txt", "nested"}}))
+	err = mRun.RunMigrationsFromDirectory("../resources/test/migrations/strict-multiple")
+	assert.Nil(err)
+}
+
+func TestParseDependencyOrder(t *testing.T) {
+	assert := assert.New(t)
+	setup()
+	defer tearDown()
+	mArr, err := parseDirectory("../resources/test/migrations/dependencies")
+	assert.Nil(err)
+	assert.Equal([]string{"1", "2", "3"}, versions(mArr))
+	assert.Nil(mRun.Migrate([]types.Migration{}))
+	status, err := mRun.GetStatus("../resources/test/migrations/dependencies")
+	assert.Nil(err)
+	assert.Equal([]string{"1", "3", "2"}, versions(status.Pending))
+
+	dir := t.TempDir() + "/"
+	os.WriteFile(dir+"3.a.query.sql", []byte("-- migrator:depends 2\nSELECT 1;"), 0644)
+	os.WriteFile(dir+"3.a.rollback.sql", []byte("SELECT 1;"), 0644)
+	_, err = parseDirectory(dir)
+	assert.Nil(err)
+	_, err = mRun.GetStatus(dir)
+	assert.ErrorContains(err, "migration '3-a' depends on version 2, which is not found")
+	err = mRun.RunMigrationsFromDirectory(dir)
+	assert.ErrorContains(err, "migration '3-a' depends on version 2, which is not found")
+
+	// Dependency on an applied version is satisfied, even after its files are removed from the directory
+	assert.Nil(mRun.Migrate([]types.Migration{dependentMigration("2", "")}))
+	status, err = mRun.GetStatus(dir)
+	assert.Nil(err)
+	assert.Equal([]string{"3"}, versions(status.Pending))
+	assert.Nil(mRun.RunMigrationsFromDirectory(dir))
+}
@@ -0,0 +1,74 @@
+package migrator
+
+import (
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/wizards-0/go-pins/logger"
+	"github.com/wizards-0/go-pins/migrator/types"
+)
+
+const DIRECTIVE_REQUIRE = "require"
+
+// What to do, when a precondition does not hold
+const (
+	PRECONDITION_FAIL = "fail"
+	PRECONDITION_SKIP = "skip"
+	PRECONDITION_WARN = "warn"
+)
+
+// Query which has to return a truthy value (true, non zero number, non empty string) in its first column,
+// for the migration to be executed. Declared as '-- migrator:require[:fail|:skip|:warn] <sql>'
+type precondition struct {
+	query  string
+	onFail string
+	line   int
+}
+
+func parsePreconditions(m types.Migration) ([]precondition, error) {
+	preconditions := []precondition{}
+	for _, d := range parseDirectives(m.Query) {
+		if d.name != DIRECTIVE_REQUIRE {
+			continue
+		}
+		p := precondition{query: d.value, onFail: d.mode, line: d.line}
+		if p.onFail == "" {
+			p.onFail = PRECONDITION_FAIL
+		}
+		if p.onFail != PRECONDITION_FAIL && p.onFail != PRECONDITION_SKIP && p.onFail != PRECONDITION_WARN {
+			return nil, fmt.Errorf("invalid precondition mode '%v' at line %v for migration '%v-%v'. Valid modes are fail | skip | warn", p.onFail, p.line, m.Version, m.Name)
+		}
+		if p.query == "" {
+			return nil, fmt.Errorf("missing precondition query at line %v for migration '%v-%v'", p.line, m.Version, m.Name)
+		}
+		preconditions = append(preconditions, p)
+	}
+	return preconditions, nil
+}
+
+// Returns true, if the migration has to be skipped
+func (migrator *migrator) checkPreconditions(tx *sqlx.Tx, m types.Migration) (bool, error) {
+	preconditions, parseErr := parsePreconditions(m)
+	if parseErr != nil {
+		return false, withKind(ErrValidation, parseErr)
+	}
+	for _, p := range preconditions {
+		holds, err := migrator.dao.CheckPrecondition(tx, p.query)
+		if err != nil {
+			return false, fmt.Errorf("error while checking precondition at line %v for migration '%v-%v'\n%w", p.line, m.Version, m.Name, err)
+		}
+		if holds {
+			continue
+		}
+		switch p.onFail {
+		case PRECONDITION_SKIP:
+			logger.Info(fmt.Sprintf("Precondition '%v' failed for migration '%v-%v', skipping it", p.query, m.Version, m.Name))
+			return true, nil
+		case PRECONDITION_WARN:
+			logger.Info(fmt.Sprintf("Precondition '%v' failed for migration '%v-%v', executing it anyway", p.query, m.Version, m.Name))
+		default:
+			return false, withKind(ErrValidation, fmt.Errorf("precondition '%v' failed for migration '%v-%v'", p.query, m.Version, m.Name))
+		}
+	}
+	return false, nil
+}
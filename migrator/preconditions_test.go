@@ -0,0 +1,135 @@
+package migrator
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wizards-0/go-pins/migrator/types"
+	mocks "github.com/wizards-0/go-pins/mocks/migrator/dao"
+)
+
+func TestParseDirectives(t *testing.T) {
+	assert := assert.New(t)
+	directives := parseDirectives(`
+-- A plain comment
+-- migrator:require SELECT 1
+  -- migrator:require:skip   SELECT COUNT(*) FROM TEST
+-- migrator:other
+CREATE TABLE A(ID INT);
+-- migrator:require SELECT 0`)
+	assert.Equal([]directive{
+		{name: "require", value: "SELECT 1", line: 3},
+		{name: "require", mode: "skip", value: "SELECT COUNT(*) FROM TEST", line: 4},
+		{name: "other", line: 5},
+	}, directives)
+}
+
+func TestParsePreconditions(t *testing.T) {
+	assert := assert.New(t)
+	preconditions, err := parsePreconditions(types.Migration{Query: "-- migrator:require SELECT 1\n-- migrator:require:warn SELECT 2\nSELECT 3"})
+	assert.Nil(err)
+	assert.Equal([]precondition{
+		{query: "SELECT 1", onFail: PRECONDITION_FAIL, line: 1},
+		{query: "SELECT 2", onFail: PRECONDITION_WARN, line: 2},
+	}, preconditions)
+
+	_, err = parsePreconditions(types.Migration{Version: "1", Name: "test", Query: "-- migrator:require:ignore SELECT 1"})
+	assert.ErrorContains(err, "invalid precondition mode 'ignore' at line 1 for migration '1-test'")
+
+	_, err = parsePreconditions(types.Migration{Version: "1", Name: "test", Query: "-- migrator:require"})
+	assert.ErrorContains(err, "missing precondition query at line 1 for migration '1-test'")
+
+	err = validateMigrations([]types.Migration{{Version: "1", Name: "test", Query: "-- migrator:require:ignore SELECT 1", Rollback: "SELECT 1"}})
+	assert.ErrorContains(err, "invalid precondition mode")
+}
+
+func TestPreconditions(t *testing.T) {
+	assert := assert.New(t)
+	setup()
+	defer tearDown()
+
+	requireTest := types.Migration{Name: "Insert test data", Version: "2",
+		Query:    "-- migrator:require SELECT COUNT(*) FROM sqlite_master WHERE name = 'TEST'\nINSERT INTO TEST VALUES (1);",
+		Rollback: "DELETE FROM TEST;",
+	}
+	err := mRun.Migrate([]types.Migration{requireTest})
+	assert.ErrorContains(err, "precondition 'SELECT COUNT(*) FROM sqlite_master WHERE name = 'TEST'' failed for migration '2-Insert test data'")
+	assert.ErrorIs(err, ErrValidation)
+
+	err = mRun.Migrate([]types.Migration{q1, requireTest})
+	assert.Nil(err)
+	mLogs, _ := mRun.GetMigrationLogs()
+	assert.Equal(2, len(mLogs))
+}
+
+func TestPreconditionSkipAndWarn(t *testing.T) {
+	assert := assert.New(t)
+	setup()
+	defer tearDown()
+
+	skipped := types.Migration{Name: "Skipped", Version: "2",
+		Query:    "-- migrator:require:skip SELECT 0\nCREATE TABLE SKIPPED(ID INT);",
+		Rollback: "DROP TABLE SKIPPED;",
+	}
+	warned := types.Migration{Name: "Warned", Version: "3",
+		Query:    "-- migrator:require:warn SELECT NULL\nCREATE TABLE WARNED(ID INT);",
+		Rollback: "DROP TABLE WARNED;",
+	}
+	buf.Reset()
+	err := mRun.Migrate([]types.Migration{q1, skipped, warned})
+	assert.Nil(err)
+	assert.Contains(buf.String(), "Precondition 'SELECT 0' failed for migration '2-Skipped', skipping it")
+	assert.Contains(buf.String(), "Precondition 'SELECT NULL' failed for migration '3-Warned', executing it anyway")
+
+	mLogs, _ := mRun.GetMigrationLogs()
+	assert.Equal(2, len(mLogs))
+	assert.Equal("3", mLogs[1].Version)
+	history, _ := mRun.GetMigrationHistory("2")
+	assert.Equal(1, len(history))
+	assert.Equal(types.HISTORY_ACTION_SKIP, history[0].Action)
+
+	// Skip is recorded again only when the migration has changed since the latest skip
+	err = mRun.Migrate([]types.Migration{q1, skipped, warned})
+	assert.Nil(err)
+	history, _ = mRun.GetMigrationHistory("2")
+	assert.Equal(1, len(history))
+
+	skipped.Query = "-- migrator:require:skip SELECT 0\nCREATE TABLE SKIPPED(ID BIGINT);"
+	err = mRun.Migrate([]types.Migration{q1, skipped, warned})
+	assert.Nil(err)
+	err = mRun.Migrate([]types.Migration{q1, skipped, warned})
+	assert.Nil(err)
+	history, _ = mRun.GetMigrationHistory("2")
+	assert.Equal(2, len(history))
+	assert.NotEqual(history[0].Hash, history[1].Hash)
+}
+
+func TestSkipHistoryError(t *testing.T) {
+	assert := assert.New(t)
+	setup()
+	defer tearDown()
+	mockDao := mocks.NewMockMigrationDao(mDao, t)
+	mRun = newMigrator(db, mockDao)
+
+	skipped := types.Migration{Name: "Skipped", Version: "2", Query: "-- migrator:require:skip SELECT 0", Rollback: "SELECT 1"}
+	mockDao.EXPECT().CheckPrecondition(TYPE_TX, "SELECT 0").Return(false, nil)
+	mockDao.EXPECT().GetMigrationHistory(TYPE_TX, "2").Return(nil, errors.New("history error"))
+	err := mRun.(*migrator).executeQuery(skipped, 1, "")
+	assert.ErrorContains(err, "error while getting migration history for '2-Skipped'\nhistory error")
+}
+
+func TestPreconditionErrors(t *testing.T) {
+	assert := assert.New(t)
+	setup()
+	defer tearDown()
+	mockDao := mocks.NewMockMigrationDao(mDao, t)
+	mRun = newMigrator(db, mockDao)
+
+	mockDao.EXPECT().CheckPrecondition(TYPE_TX, "SELECT 1").Return(false, errors.New("check error"))
+	err := mRun.(*migrator).executeQuery(types.Migration{Version: "1", Name: "test", Query: "-- migrator:require SELECT 1"}, 1, "")
+	assert.ErrorContains(err, "error while checking precondition at line 1 for migration '1-test'")
+
+	err = mRun.(*migrator).executeQuery(types.Migration{Version: "1", Name: "test", Query: "-- migrator:require:bad SELECT 1"}, 1, "")
+	assert.ErrorIs(err, ErrValidation)
+}
@@ -0,0 +1,89 @@
+package migrator
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mattn/go-sqlite3"
+	"github.com/wizards-0/go-pins/logger"
+)
+
+type RetryPolicy struct {
+	// Total no. of attempts, including the first one. Values below 1 are treated as 1
+	MaxAttempts    int
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	// Factor by which backoff grows after each attempt. Values below 1 are treated as 1
+	Multiplier float64
+	// Classifies errors, which are worth retrying. Defaults to IsTransientError
+	IsTransient func(err error) bool
+}
+
+// Used by default, executes migrations only once
+var NoRetry = RetryPolicy{MaxAttempts: 1}
+
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:    5,
+		InitialBackoff: 100 * time.Millisecond,
+		MaxBackoff:     5 * time.Second,
+		Multiplier:     2,
+		IsTransient:    IsTransientError,
+	}
+}
+
+// Lower case fragments of error messages, which drivers use for busy / lock / serialization failures
+var transientMessages = []string{
+	"database is locked",
+	"database table is locked",
+	"lock wait timeout",
+	"lock timeout",
+	"deadlock",
+	"could not serialize access",
+	"serialization failure",
+	"sqlstate 40001",
+	"sqlstate 40p01",
+}
+
+// Returns true for errors caused by contention, e.g. SQLITE_BUSY, lock timeouts, deadlocks or serialization failures
+func IsTransientError(err error) bool {
+	if err == nil {
+		return false
+	}
+	var sqliteErr sqlite3.Error
+	if errors.As(err, &sqliteErr) {
+		return sqliteErr.Code == sqlite3.ErrBusy || sqliteErr.Code == sqlite3.ErrLocked
+	}
+	msg := strings.ToLower(err.Error())
+	for _, fragment := range transientMessages {
+		if strings.Contains(msg, fragment) {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *migrator) withRetry(description string, fn func() error) error {
+	p := m.retryPolicy
+	attempts := max(p.MaxAttempts, 1)
+	isTransient := p.IsTransient
+	if isTransient == nil {
+		isTransient = IsTransientError
+	}
+	backoff := p.InitialBackoff
+	for attempt := 1; ; attempt++ {
+		logger.Debug(fmt.Sprintf("Attempt %v of %v for %v", attempt, attempts, description))
+		err := fn()
+		if err == nil || attempt >= attempts || !isTransient(err) {
+			return err
+		}
+		logger.Info(fmt.Sprintf("Attempt %v of %v for %v failed with transient error, retrying in %v. %v", attempt, attempts, description, backoff, err))
+		m.sleep(backoff)
+		backoff = time.Duration(float64(backoff) * max(p.Multiplier, 1))
+		if p.MaxBackoff > 0 {
+			backoff = min(backoff, p.MaxBackoff)
+		}
+	}
+}
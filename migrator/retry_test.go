@@ -0,0 +1,78 @@
+package migrator
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	"github.com/wizards-0/go-pins/migrator/types"
+	mocks "github.com/wizards-0/go-pins/mocks/migrator/dao"
+)
+
+func TestIsTransientError(t *testing.T) {
+	assert := assert.New(t)
+	assert.False(IsTransientError(nil))
+	assert.False(IsTransientError(errors.New("syntax error")))
+	assert.True(IsTransientError(fmt.Errorf("wrapped\n%w", sqlite3.Error{Code: sqlite3.ErrBusy})))
+	assert.True(IsTransientError(sqlite3.Error{Code: sqlite3.ErrLocked}))
+	assert.False(IsTransientError(sqlite3.Error{Code: sqlite3.ErrConstraint}))
+	assert.True(IsTransientError(errors.New("Error 1205: Lock wait timeout exceeded; try restarting transaction")))
+	assert.True(IsTransientError(errors.New("pq: could not serialize access due to concurrent update")))
+	assert.True(IsTransientError(errors.New("ERROR: deadlock detected (SQLSTATE 40P01)")))
+}
+
+func TestRetryOnTransientError(t *testing.T) {
+	assert := assert.New(t)
+	setup()
+	defer tearDown()
+	mRun.Migrate([]types.Migration{})
+	mockDao := mocks.NewMockMigrationDao(mDao, t)
+	mRun = newMigrator(db, mockDao, WithRetryPolicy(RetryPolicy{
+		MaxAttempts:    4,
+		InitialBackoff: 10 * time.Millisecond,
+		MaxBackoff:     15 * time.Millisecond,
+		Multiplier:     2,
+	}))
+	sleeps := []time.Duration{}
+	mRun.(*migrator).sleep = func(d time.Duration) {
+		sleeps = append(sleeps, d)
+	}
+
+	mockDao.EXPECT().ExecuteQuery(TYPE_TX, TYPE_MIGRATION).Return(sqlite3.Error{Code: sqlite3.ErrBusy}).Times(2)
+	mockDao.PassThrough("ExecuteQuery", "InsertMigrationLog", "InsertMigrationHistory")
+	buf.Reset()
+	err := mRun.(*migrator).executeQuery(q1, 1, hashQuery(q1.Query))
+	assert.Nil(err)
+	assert.Equal([]time.Duration{10 * time.Millisecond, 15 * time.Millisecond}, sleeps)
+	assert.Contains(buf.String(), "Attempt 2 of 4 for migration '1-Create test table' failed with transient error, retrying in 15ms")
+
+	mockDao.PassThrough("GetMigrationLogs")
+	mockDao.EXPECT().ExecuteRollback(TYPE_TX, TYPE_MIGRATION).Return(errors.New("database is locked")).Times(4)
+	err = mRun.Rollback("0")
+	assert.ErrorContains(err, "database is locked")
+	assert.ErrorIs(err, ErrExecution)
+	assert.Equal(5, len(sleeps))
+}
+
+func TestNoRetryOnPermanentError(t *testing.T) {
+	assert := assert.New(t)
+	setup()
+	defer tearDown()
+	mockDao := mocks.NewMockMigrationDao(mDao, t)
+	mRun = newMigrator(db, mockDao, WithRetryPolicy(DefaultRetryPolicy()))
+	mRun.(*migrator).sleep = func(d time.Duration) {
+		assert.Fail("unexpected retry")
+	}
+
+	mockDao.EXPECT().ExecuteQuery(TYPE_TX, TYPE_MIGRATION).Return(errors.New("syntax error")).Once()
+	err := mRun.(*migrator).executeQuery(q1, 1, hashQuery(q1.Query))
+	assert.ErrorContains(err, "syntax error")
+
+	mRun = newMigrator(db, mockDao)
+	mockDao.EXPECT().ExecuteQuery(TYPE_TX, TYPE_MIGRATION).Return(sqlite3.Error{Code: sqlite3.ErrBusy}).Once()
+	err = mRun.(*migrator).executeQuery(q1, 1, hashQuery(q1.Query))
+	assert.ErrorIs(err, ErrExecution)
+}
@@ -0,0 +1,389 @@
+package seed
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/wizards-0/go-pins/logger"
+	"github.com/wizards-0/go-pins/pins"
+	"github.com/wizards-0/go-pins/slu"
+)
+
+type Mode string
+
+const (
+	// Rows conflicting with existing rows are left unchanged
+	MODE_SKIP Mode = "skip"
+	// Rows conflicting on the table keys overwrite existing rows
+	MODE_UPSERT Mode = "upsert"
+)
+
+const DEFAULT_CHUNK_SIZE = 100
+
+var identifierPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*(\.[A-Za-z_][A-Za-z0-9_]*)?$`)
+
+// Seed file, applied to the database
+type SeedLog struct {
+	File  string `db:"file" json:"file"`
+	Table string `db:"tbl" json:"table"`
+	Rows  int    `db:"row_count" json:"rows"`
+	Date  int64  `db:"date" json:"date"`
+	Hash  string `db:"hash" json:"hash"`
+}
+
+type Config struct {
+	// Defaults to MODE_SKIP
+	Mode Mode
+	// Rows per insert statement. Defaults to DEFAULT_CHUNK_SIZE
+	ChunkSize int
+	// Conflict key columns by table. Required for tables seeded in MODE_UPSERT
+	Keys map[string][]string
+}
+
+// Loads reference data from seed files named '<order>.<table>.csv' or '<order>.<table>.json', e.g. '1.roles.csv'.
+// CSV files have column names in the header row, and empty cells are inserted as NULL.
+// JSON files contain an array of objects, keyed by column name.
+// Files are applied in order, each in its own transaction. Files already applied with the same hash are skipped,
+// and modified files are applied again.
+// Conflicts are resolved with 'ON CONFLICT' clause, supported by sqlite & postgres.
+type Seeder interface {
+	SeedFromDirectory(path string) ([]SeedLog, error)
+	GetSeedLogs() ([]SeedLog, error)
+}
+
+func New(db *sqlx.DB, schema string, cfg Config) Seeder {
+	tablePrefix := ""
+	if schema != "" {
+		tablePrefix = schema + "."
+	}
+	if cfg.Mode == "" {
+		cfg.Mode = MODE_SKIP
+	}
+	if cfg.ChunkSize <= 0 {
+		cfg.ChunkSize = DEFAULT_CHUNK_SIZE
+	}
+	return &seeder{db: db, cfg: cfg, seedTable: tablePrefix + "seed_log"}
+}
+
+type seeder struct {
+	db        *sqlx.DB
+	cfg       Config
+	seedTable string
+}
+
+type seedFile struct {
+	name  string
+	path  string
+	order int
+	table string
+}
+
+type seedData struct {
+	columns []string
+	rows    [][]any
+}
+
+// Applies new & modified seed files in the directory. Returns the files applied
+func (s *seeder) SeedFromDirectory(path string) ([]SeedLog, error) {
+	files, err := listSeedFiles(path)
+	if err != nil {
+		return nil, fmt.Errorf("error while seeding from path %v\n%w", path, err)
+	}
+	var setupErr error
+	txErr := slu.WithDefaultCtxTx(s.db, func(tx *sqlx.Tx) bool {
+		setupErr = s.setupSeedTable(tx)
+		return setupErr == nil
+	})
+	if err := pins.MergeErrors(txErr, setupErr); err != nil {
+		return nil, fmt.Errorf("error while seeding from path %v\n%w", path, err)
+	}
+	applied := []SeedLog{}
+	for _, f := range files {
+		sLog, seeded, err := s.applyFile(f)
+		if err != nil {
+			return applied, fmt.Errorf("error while seeding from path %v\n%w", path, err)
+		}
+		if seeded {
+			applied = append(applied, sLog)
+		}
+	}
+	return applied, nil
+}
+
+func (s *seeder) GetSeedLogs() (sLogs []SeedLog, err error) {
+	txErr := slu.WithDefaultCtxTx(s.db, func(tx *sqlx.Tx) bool {
+		sLogs = []SeedLog{}
+		if err = tx.Select(&sLogs, "SELECT file, tbl, row_count, date, hash FROM "+s.seedTable+" ORDER BY file"); err != nil {
+			err = logger.WrapAndLogError(err, "error while getting seed logs from db")
+		}
+		return err == nil
+	})
+	return sLogs, pins.MergeErrors(txErr, err)
+}
+
+func listSeedFiles(path string) ([]seedFile, error) {
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return nil, fmt.Errorf("error in reading directory - %v\n%w", path, err)
+	}
+	files := []seedFile{}
+	for _, e := range entries {
+		if e.IsDir() || strings.HasPrefix(e.Name(), ".") {
+			continue
+		}
+		f, err := parseFileName(e.Name())
+		if err != nil {
+			return nil, err
+		}
+		f.path = filepath.Join(path, e.Name())
+		files = append(files, f)
+	}
+	sort.SliceStable(files, func(i1, i2 int) bool {
+		return files[i1].order < files[i2].order
+	})
+	return files, nil
+}
+
+func parseFileName(name string) (seedFile, error) {
+	ext := filepath.Ext(name)
+	if ext != ".csv" && ext != ".json" {
+		return seedFile{}, fmt.Errorf("invalid seed file '%v', expected extension .csv or .json", name)
+	}
+	orderStr, table, found := strings.Cut(strings.TrimSuffix(name, ext), ".")
+	order, err := strconv.Atoi(orderStr)
+	if !found || err != nil {
+		return seedFile{}, fmt.Errorf("invalid seed file '%v', expected name of format <order>.<table>%v", name, ext)
+	}
+	if !identifierPattern.MatchString(table) {
+		return seedFile{}, fmt.Errorf("invalid table name '%v' in seed file '%v'", table, name)
+	}
+	return seedFile{name: name, order: order, table: table}, nil
+}
+
+// Returns true, if the file was seeded. Files already applied with the same hash are skipped
+func (s *seeder) applyFile(f seedFile) (sLog SeedLog, seeded bool, err error) {
+	content, readErr := os.ReadFile(f.path)
+	if readErr != nil {
+		return SeedLog{}, false, fmt.Errorf("error in reading seed file %v\n%w", f.path, readErr)
+	}
+	data, parseErr := parseSeedData(f, content)
+	if parseErr != nil {
+		return SeedLog{}, false, fmt.Errorf("error in parsing seed file %v\n%w", f.path, parseErr)
+	}
+	sLog = SeedLog{File: f.name, Table: f.table, Rows: len(data.rows), Hash: hashContent(content)}
+
+	txErr := slu.WithDefaultCtxTx(s.db, func(tx *sqlx.Tx) bool {
+		var existing []SeedLog
+		if err = tx.Select(&existing, tx.Rebind("SELECT file, tbl, row_count, date, hash FROM "+s.seedTable+" WHERE file=?"), f.name); err != nil {
+			err = logger.WrapAndLogError(err, "error while getting seed log for file "+f.name)
+			return false
+		}
+		if len(existing) > 0 && existing[0].Hash == sLog.Hash {
+			logger.Debug(fmt.Sprintf("Seed file %v is already applied", f.name))
+			return true
+		}
+		if len(existing) > 0 {
+			logger.Info(fmt.Sprintf("Seed file %v is modified since it was applied, applying it again", f.name))
+		}
+		if err = s.insertRows(tx, f.table, data); err != nil {
+			return false
+		}
+		sLog.Date = time.Now().UnixMilli()
+		if err = s.saveSeedLog(tx, sLog, len(existing) > 0); err != nil {
+			return false
+		}
+		seeded = true
+		return true
+	})
+	if err = pins.MergeErrors(txErr, err); err != nil {
+		return SeedLog{}, false, fmt.Errorf("error while applying seed file %v\n%w", f.name, err)
+	}
+	if seeded {
+		logger.Info(fmt.Sprintf("Seeded %v rows into %v from %v", sLog.Rows, sLog.Table, f.name))
+	}
+	return sLog, seeded, nil
+}
+
+func parseSeedData(f seedFile, content []byte) (seedData, error) {
+	var data seedData
+	var err error
+	if filepath.Ext(f.name) == ".csv" {
+		data, err = parseCsv(content)
+	} else {
+		data, err = parseJson(content)
+	}
+	if err != nil {
+		return seedData{}, err
+	}
+	for _, col := range data.columns {
+		if !identifierPattern.MatchString(col) {
+			return seedData{}, fmt.Errorf("invalid column name '%v'", col)
+		}
+	}
+	return data, nil
+}
+
+func parseCsv(content []byte) (seedData, error) {
+	records, err := csv.NewReader(bytes.NewReader(content)).ReadAll()
+	if err != nil {
+		return seedData{}, err
+	}
+	if len(records) == 0 {
+		return seedData{}, errors.New("missing header row")
+	}
+	data := seedData{columns: records[0], rows: [][]any{}}
+	for _, record := range records[1:] {
+		row := make([]any, len(record))
+		for i, v := range record {
+			if v != "" {
+				row[i] = v
+			}
+		}
+		data.rows = append(data.rows, row)
+	}
+	return data, nil
+}
+
+// Columns are the union of keys of all objects, missing keys are inserted as NULL.
+// Nested objects and arrays are inserted as json text
+func parseJson(content []byte) (seedData, error) {
+	decoder := json.NewDecoder(bytes.NewReader(content))
+	decoder.UseNumber()
+	objects := []map[string]any{}
+	if err := decoder.Decode(&objects); err != nil {
+		return seedData{}, err
+	}
+	colSet := map[string]bool{}
+	for _, o := range objects {
+		for k := range o {
+			colSet[k] = true
+		}
+	}
+	data := seedData{columns: []string{}, rows: [][]any{}}
+	for col := range colSet {
+		data.columns = append(data.columns, col)
+	}
+	slices.Sort(data.columns)
+	for _, o := range objects {
+		row := make([]any, len(data.columns))
+		for i, col := range data.columns {
+			v, err := jsonValue(o[col])
+			if err != nil {
+				return seedData{}, fmt.Errorf("error in converting value of column '%v'\n%w", col, err)
+			}
+			row[i] = v
+		}
+		data.rows = append(data.rows, row)
+	}
+	return data, nil
+}
+
+func jsonValue(v any) (any, error) {
+	switch val := v.(type) {
+	case map[string]any, []any:
+		b, err := json.Marshal(val)
+		return string(b), err
+	case json.Number:
+		return val.String(), nil
+	default:
+		return val, nil
+	}
+}
+
+func (s *seeder) insertRows(tx *sqlx.Tx, table string, data seedData) error {
+	if len(data.rows) == 0 {
+		return nil
+	}
+	conflictClause, err := s.getConflictClause(table, data.columns)
+	if err != nil {
+		return err
+	}
+	accessors := make([]func(row []any) any, len(data.columns))
+	for i := range data.columns {
+		accessors[i] = func(row []any) any {
+			return row[i]
+		}
+	}
+	for start := 0; start < len(data.rows); start += s.cfg.ChunkSize {
+		chunk := data.rows[start:min(start+s.cfg.ChunkSize, len(data.rows))]
+		query := "INSERT INTO " + table + " (" + strings.Join(data.columns, ", ") + ") VALUES " +
+			slu.GetInsertPlaceholders(len(data.columns), len(chunk)) + conflictClause
+		if _, err := tx.Exec(tx.Rebind(query), slu.FlattenStructs(chunk, accessors)...); err != nil {
+			return logger.LogError(fmt.Errorf("error in database while inserting rows %v to %v into %v\n%w", start+1, start+len(chunk), table, err))
+		}
+	}
+	return nil
+}
+
+func (s *seeder) getConflictClause(table string, columns []string) (string, error) {
+	keys := s.cfg.Keys[table]
+	for _, key := range keys {
+		if !identifierPattern.MatchString(key) {
+			return "", fmt.Errorf("invalid conflict key column '%v' for table %v", key, table)
+		}
+	}
+	if s.cfg.Mode == MODE_SKIP {
+		if len(keys) == 0 {
+			return " ON CONFLICT DO NOTHING", nil
+		}
+		return " ON CONFLICT (" + strings.Join(keys, ", ") + ") DO NOTHING", nil
+	}
+	if len(keys) == 0 {
+		return "", fmt.Errorf("missing conflict keys for table %v, keys are required in %v mode", table, s.cfg.Mode)
+	}
+	updates := []string{}
+	for _, col := range columns {
+		if !slices.Contains(keys, col) {
+			updates = append(updates, col+" = excluded."+col)
+		}
+	}
+	if len(updates) == 0 {
+		return " ON CONFLICT (" + strings.Join(keys, ", ") + ") DO NOTHING", nil
+	}
+	return " ON CONFLICT (" + strings.Join(keys, ", ") + ") DO UPDATE SET " + strings.Join(updates, ", "), nil
+}
+
+func (s *seeder) saveSeedLog(tx *sqlx.Tx, sLog SeedLog, exists bool) error {
+	query := "INSERT INTO " + s.seedTable + " (file, tbl, row_count, date, hash) VALUES (:file, :tbl, :row_count, :date, :hash)"
+	if exists {
+		query = "UPDATE " + s.seedTable + " SET tbl=:tbl, row_count=:row_count, date=:date, hash=:hash WHERE file=:file"
+	}
+	if _, err := tx.NamedExec(query, &sLog); err != nil {
+		return logger.LogError(fmt.Errorf("error in database while saving seed log for file %v\n%w", sLog.File, err))
+	}
+	return nil
+}
+
+func (s *seeder) setupSeedTable(tx *sqlx.Tx) error {
+	_, err := tx.Exec(`CREATE TABLE IF NOT EXISTS ` + s.seedTable + ` (
+		file VARCHAR(200) PRIMARY KEY,
+		tbl VARCHAR(200),
+		row_count INTEGER,
+		date BIGINT,
+		hash VARCHAR(64)
+	);`)
+	if err != nil {
+		return logger.LogError(fmt.Errorf("error in creating seed_log table\n%w", err))
+	}
+	return nil
+}
+
+func hashContent(content []byte) string {
+	hasher := sha256.New()
+	hasher.Write(content)
+	return base64.URLEncoding.EncodeToString(hasher.Sum(nil))
+}
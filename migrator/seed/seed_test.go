@@ -0,0 +1,188 @@
+package seed
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jmoiron/sqlx"
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	"github.com/wizards-0/go-pins/logger"
+)
+
+const VALID_PATH = "../../resources/test/seeds/valid"
+
+var buf = bytes.Buffer{}
+var db *sqlx.DB
+
+func setup() {
+	w := &buf
+	logger.SetWriter(w, w, w, w)
+	logger.SetLogLevel(logger.LOG_LEVEL_DEBUG)
+	db = sqlx.MustConnect("sqlite3", ":memory:")
+	db.SetMaxOpenConns(1)
+	db.MustExec("CREATE TABLE roles(id INTEGER PRIMARY KEY, name VARCHAR(20), description VARCHAR(200))")
+	db.MustExec("CREATE TABLE users(id INTEGER PRIMARY KEY, name VARCHAR(20), role_id INTEGER, meta TEXT, active BOOLEAN)")
+}
+
+func tearDown() {
+	db.Close()
+}
+
+type role struct {
+	Id          int     `db:"id"`
+	Name        string  `db:"name"`
+	Description *string `db:"description"`
+}
+
+type user struct {
+	Id     int     `db:"id"`
+	Name   string  `db:"name"`
+	RoleId int     `db:"role_id"`
+	Meta   *string `db:"meta"`
+	Active *bool   `db:"active"`
+}
+
+func TestSeedFromDirectory(t *testing.T) {
+	assert := assert.New(t)
+	setup()
+	defer tearDown()
+	s := New(db, "", Config{ChunkSize: 2})
+	applied, err := s.SeedFromDirectory(VALID_PATH)
+	assert.Nil(err)
+	assert.Equal(2, len(applied))
+	assert.Equal("1.roles.csv", applied[0].File)
+	assert.Equal("users", applied[1].Table)
+	assert.Equal(3, applied[1].Rows)
+
+	roles := []role{}
+	assert.Nil(db.Select(&roles, "SELECT * FROM roles ORDER BY id"))
+	assert.Equal(3, len(roles))
+	assert.Nil(roles[1].Description)
+	assert.Equal("Read only, limited", *roles[2].Description)
+
+	users := []user{}
+	assert.Nil(db.Select(&users, "SELECT * FROM users ORDER BY id"))
+	assert.Equal(3, len(users))
+	assert.Equal(`{"tags":["a","b"]}`, *users[0].Meta)
+	assert.Nil(users[1].Active)
+	assert.True(*users[2].Active)
+	assert.Equal(3, users[2].RoleId)
+
+	// Applied files are not seeded again
+	applied, err = s.SeedFromDirectory(VALID_PATH)
+	assert.Nil(err)
+	assert.Equal(0, len(applied))
+	sLogs, err := s.GetSeedLogs()
+	assert.Nil(err)
+	assert.Equal(2, len(sLogs))
+	content, _ := os.ReadFile(filepath.Join(VALID_PATH, "1.roles.csv"))
+	assert.Equal(hashContent(content), sLogs[0].Hash)
+}
+
+func TestSeedModes(t *testing.T) {
+	assert := assert.New(t)
+	setup()
+	defer tearDown()
+	dir := t.TempDir()
+	writeFile(t, dir, "1.roles.csv", "id,name\n1,admin\n2,user\n")
+	_, err := New(db, "", Config{}).SeedFromDirectory(dir)
+	assert.Nil(err)
+
+	// Modified file is applied again, and conflicting rows are skipped
+	writeFile(t, dir, "1.roles.csv", "id,name\n1,root\n2,member\n3,guest\n")
+	buf.Reset()
+	applied, err := New(db, "", Config{}).SeedFromDirectory(dir)
+	assert.Nil(err)
+	assert.Equal(1, len(applied))
+	assert.Contains(buf.String(), "Seed file 1.roles.csv is modified since it was applied, applying it again")
+	names := []string{}
+	assert.Nil(db.Select(&names, "SELECT name FROM roles ORDER BY id"))
+	assert.Equal([]string{"admin", "user", "guest"}, names)
+
+	writeFile(t, dir, "1.roles.csv", "id,name\n1,root\n2,member\n")
+	_, err = New(db, "", Config{Mode: MODE_UPSERT}).SeedFromDirectory(dir)
+	assert.ErrorContains(err, "missing conflict keys for table roles, keys are required in upsert mode")
+	_, err = New(db, "", Config{Mode: MODE_UPSERT, Keys: map[string][]string{"roles": {"id) DO NOTHING; DROP TABLE roles; --"}}}).SeedFromDirectory(dir)
+	assert.ErrorContains(err, "invalid conflict key column 'id) DO NOTHING; DROP TABLE roles; --' for table roles")
+
+	_, err = New(db, "", Config{Mode: MODE_UPSERT, Keys: map[string][]string{"roles": {"id"}}}).SeedFromDirectory(dir)
+	assert.Nil(err)
+	names = []string{}
+	assert.Nil(db.Select(&names, "SELECT name FROM roles ORDER BY id"))
+	assert.Equal([]string{"root", "member", "guest"}, names)
+}
+
+func TestSeedErrors(t *testing.T) {
+	assert := assert.New(t)
+	setup()
+	defer tearDown()
+	s := New(db, "", Config{})
+	_, err := s.SeedFromDirectory("../../resources/test/seeds/invalid-name")
+	assert.ErrorContains(err, "invalid seed file 'roles.csv', expected name of format <order>.<table>.csv")
+
+	_, err = s.SeedFromDirectory("../invalid-path")
+	assert.ErrorContains(err, "error in reading directory - ../invalid-path")
+
+	dir := t.TempDir()
+	writeFile(t, dir, "1.roles.txt", "")
+	_, err = s.SeedFromDirectory(dir)
+	assert.ErrorContains(err, "invalid seed file '1.roles.txt', expected extension .csv or .json")
+
+	dir = t.TempDir()
+	writeFile(t, dir, "1.roles;drop.csv", "id\n1\n")
+	_, err = s.SeedFromDirectory(dir)
+	assert.ErrorContains(err, "invalid table name 'roles;drop' in seed file '1.roles;drop.csv'")
+
+	dir = t.TempDir()
+	writeFile(t, dir, "1.roles.csv", "id,name\n1,admin\n")
+	writeFile(t, dir, "2.users.json", `[{"id": 1, "missing_column": 1}]`)
+	applied, err := s.SeedFromDirectory(dir)
+	assert.ErrorContains(err, "error while applying seed file 2.users.json")
+	assert.ErrorContains(err, "error in database while inserting rows 1 to 1 into users")
+	assert.Equal(1, len(applied))
+	sLogs, _ := s.GetSeedLogs()
+	assert.Equal(1, len(sLogs))
+
+	dir = t.TempDir()
+	writeFile(t, dir, "1.users.json", `{"id": 1}`)
+	_, err = s.SeedFromDirectory(dir)
+	assert.ErrorContains(err, "error in parsing seed file")
+
+	dir = t.TempDir()
+	writeFile(t, dir, "1.users.csv", "id,\"bad name\"\n1,a\n")
+	_, err = s.SeedFromDirectory(dir)
+	assert.ErrorContains(err, "invalid column name 'bad name'")
+
+	dir = t.TempDir()
+	writeFile(t, dir, "1.users.csv", "")
+	_, err = s.SeedFromDirectory(dir)
+	assert.ErrorContains(err, "missing header row")
+}
+
+func TestSeedSchema(t *testing.T) {
+	assert := assert.New(t)
+	setup()
+	defer tearDown()
+	db.MustExec("ATTACH DATABASE ':memory:' AS seeds")
+	s := New(db, "seeds", Config{})
+	_, err := s.SeedFromDirectory(VALID_PATH)
+	assert.Nil(err)
+	var count int
+	assert.Nil(db.Get(&count, "SELECT COUNT(*) FROM seeds.seed_log"))
+	assert.Equal(2, count)
+
+	db.MustExec("DETACH DATABASE seeds")
+	_, err = s.GetSeedLogs()
+	assert.ErrorContains(err, "error while getting seed logs from db")
+	_, err = s.SeedFromDirectory(VALID_PATH)
+	assert.ErrorContains(err, "error in creating seed_log table")
+}
+
+func writeFile(t *testing.T, dir string, name string, content string) {
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
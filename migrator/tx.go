@@ -0,0 +1,88 @@
+package migrator
+
+import (
+	"fmt"
+	"sync/atomic"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/wizards-0/go-pins/pins"
+	"github.com/wizards-0/go-pins/slu"
+)
+
+// Runs fn in a transaction. Changes made by fn are kept if it returns true, and discarded otherwise
+type TxProvider interface {
+	WithTx(fn func(tx *sqlx.Tx) bool) error
+}
+
+type dbTxProvider struct {
+	db *sqlx.DB
+}
+
+// Opens a new transaction on db for each call. This is the default provider of the migrator
+func NewDbTxProvider(db *sqlx.DB) TxProvider {
+	return &dbTxProvider{db: db}
+}
+
+func (p *dbTxProvider) WithTx(fn func(tx *sqlx.Tx) bool) error {
+	return slu.WithDefaultCtxTx(p.db, fn)
+}
+
+type callerTxProvider struct {
+	tx         *sqlx.Tx
+	savepoints bool
+	counter    atomic.Int64
+}
+
+// Runs everything in a transaction owned by the caller, which is never committed or rolled back by the migrator.
+// With savepoints, each call runs in its own savepoint, which is released or rolled back to.
+// Without savepoints (for drivers which do not support them), changes of a failed call remain in tx,
+// and the caller has to roll it back
+func NewCallerTxProvider(tx *sqlx.Tx, savepoints bool) TxProvider {
+	return &callerTxProvider{tx: tx, savepoints: savepoints}
+}
+
+func (p *callerTxProvider) WithTx(fn func(tx *sqlx.Tx) bool) error {
+	if !p.savepoints {
+		fn(p.tx)
+		return nil
+	}
+	savepoint := fmt.Sprintf("migrator_sp_%v", p.counter.Add(1))
+	if _, err := p.tx.Exec("SAVEPOINT " + savepoint); err != nil {
+		return fmt.Errorf("error in creating savepoint %v. %w", savepoint, err)
+	}
+	if fn(p.tx) {
+		if _, err := p.tx.Exec("RELEASE SAVEPOINT " + savepoint); err != nil {
+			return fmt.Errorf("error in releasing savepoint %v. %w", savepoint, err)
+		}
+		return nil
+	}
+	if _, err := p.tx.Exec("ROLLBACK TO SAVEPOINT " + savepoint); err != nil {
+		return fmt.Errorf("error in rolling back to savepoint %v. %w", savepoint, err)
+	}
+	if _, err := p.tx.Exec("RELEASE SAVEPOINT " + savepoint); err != nil {
+		return fmt.Errorf("error in releasing savepoint %v. %w", savepoint, err)
+	}
+	return nil
+}
+
+type scopedTxProvider struct {
+	TxProvider
+	query string
+}
+
+// Runs query at the start of each transaction of p, e.g. SET search_path TO tenant_a. Fails the transaction, if query fails
+func NewScopedTxProvider(p TxProvider, query string) TxProvider {
+	return &scopedTxProvider{TxProvider: p, query: query}
+}
+
+func (p *scopedTxProvider) WithTx(fn func(tx *sqlx.Tx) bool) error {
+	var scopeErr error
+	txErr := p.TxProvider.WithTx(func(tx *sqlx.Tx) bool {
+		if _, scopeErr = tx.Exec(p.query); scopeErr != nil {
+			scopeErr = fmt.Errorf("error in scoping transaction with '%v'\n%w", p.query, scopeErr)
+			return false
+		}
+		return fn(tx)
+	})
+	return pins.MergeErrors(txErr, scopeErr)
+}
@@ -0,0 +1,119 @@
+package migrator
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/wizards-0/go-pins/migrator/types"
+)
+
+func TestCallerTx(t *testing.T) {
+	assert := assert.New(t)
+	setup()
+	defer tearDown()
+	tx := db.MustBegin()
+	tx.MustExec("CREATE TABLE FIXTURE(Id int)")
+	tx.MustExec("INSERT INTO FIXTURE VALUES (1)")
+
+	mTx := New(db, "", WithCallerTx(tx))
+	assert.Nil(mTx.Migrate([]types.Migration{q1, q2}))
+	tx.MustExec("INSERT INTO TEST VALUES (1)")
+
+	// Failed migration is rolled back to its savepoint, without affecting the rest of the transaction
+	bad := types.Migration{Name: "Bad", Version: "3", Query: "INSERT INTO TEST VALUES (2); INSERT INTO MISSING VALUES (1);", Rollback: "SELECT 1;"}
+	err := mTx.Migrate([]types.Migration{q1, q2, bad})
+	assert.ErrorIs(err, ErrExecution)
+	var count int
+	assert.Nil(tx.Get(&count, "SELECT COUNT(*) FROM TEST"))
+	assert.Equal(1, count)
+	assert.Nil(tx.Get(&count, "SELECT COUNT(*) FROM FIXTURE"))
+	assert.Equal(1, count)
+	mLogs, err := mTx.GetMigrationLogs()
+	assert.Nil(err)
+	assert.Equal(2, len(mLogs))
+
+	assert.Nil(mTx.Rollback("2"))
+	mLogs, _ = mTx.GetMigrationLogs()
+	assert.Equal(1, len(mLogs))
+
+	// Nothing is left, after the caller rolls back its transaction
+	assert.Nil(tx.Rollback())
+	var tables int
+	assert.Nil(db.Get(&tables, "SELECT COUNT(*) FROM sqlite_master WHERE name IN ('FIXTURE', 'TEST', 'migration_log')"))
+	assert.Equal(0, tables)
+}
+
+func TestCallerTxWithoutSavepoints(t *testing.T) {
+	assert := assert.New(t)
+	setup()
+	defer tearDown()
+	tx := db.MustBegin()
+	defer tx.Rollback()
+	p := NewCallerTxProvider(tx, false)
+	assert.Nil(p.WithTx(func(tx *sqlx.Tx) bool {
+		tx.MustExec("CREATE TABLE NO_SAVEPOINT(Id int)")
+		return false
+	}))
+	var tables int
+	assert.Nil(tx.Get(&tables, "SELECT COUNT(*) FROM sqlite_master WHERE name = 'NO_SAVEPOINT'"))
+	assert.Equal(1, tables)
+}
+
+func TestCallerTxErrors(t *testing.T) {
+	assert := assert.New(t)
+	setup()
+	defer tearDown()
+	tx := db.MustBegin()
+	assert.Nil(tx.Rollback())
+	p := NewCallerTxProvider(tx, true)
+	err := p.WithTx(func(tx *sqlx.Tx) bool {
+		return true
+	})
+	assert.ErrorContains(err, "error in creating savepoint migrator_sp_1")
+
+	tx = db.MustBegin()
+	p = NewCallerTxProvider(tx, true)
+	err = p.WithTx(func(tx *sqlx.Tx) bool {
+		tx.Rollback()
+		return true
+	})
+	assert.ErrorContains(err, "error in releasing savepoint migrator_sp_1")
+	err = NewCallerTxProvider(tx, true).WithTx(func(tx *sqlx.Tx) bool {
+		return false
+	})
+	assert.ErrorContains(err, "error in creating savepoint")
+
+	tx = db.MustBegin()
+	defer tx.Rollback()
+	err = NewCallerTxProvider(tx, true).WithTx(func(tx *sqlx.Tx) bool {
+		tx.MustExec("RELEASE SAVEPOINT migrator_sp_1")
+		return false
+	})
+	assert.ErrorContains(err, "error in rolling back to savepoint migrator_sp_1")
+}
+
+type countingTxProvider struct {
+	TxProvider
+	calls int
+}
+
+func (p *countingTxProvider) WithTx(fn func(tx *sqlx.Tx) bool) error {
+	p.calls++
+	if p.calls > 2 {
+		return errors.New("provider closed")
+	}
+	return p.TxProvider.WithTx(fn)
+}
+
+func TestCustomTxProvider(t *testing.T) {
+	assert := assert.New(t)
+	setup()
+	defer tearDown()
+	p := &countingTxProvider{TxProvider: NewDbTxProvider(db)}
+	mTx := New(db, "", WithTxProvider(p))
+	err := mTx.Migrate([]types.Migration{q1})
+	assert.ErrorContains(err, "provider closed")
+	assert.Equal(3, p.calls)
+}
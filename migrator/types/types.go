@@ -2,6 +2,15 @@ package types
 
 const VERSION_SEPARATOR = "-"
 
+const (
+	HISTORY_ACTION_APPLY    = "apply"
+	HISTORY_ACTION_ROLLBACK = "rollback"
+	HISTORY_ACTION_SKIP     = "skip"
+	HISTORY_ACTION_IMPORT   = "import"
+	HISTORY_ACTION_BASELINE = "baseline"
+	HISTORY_ACTION_REPAIR   = "repair"
+)
+
 type MigrationLog struct {
 	Id int `db:"id" json:"id"`
 	Migration
@@ -15,3 +24,32 @@ type Migration struct {
 	Query    string `db:"query" json:"query"`
 	Rollback string `db:"rollback" json:"rollback"`
 }
+
+// Audit entry, recorded for every action performed on a migration
+type MigrationHistory struct {
+	Id      int    `db:"id" json:"id"`
+	Version string `db:"version" json:"version"`
+	Name    string `db:"name" json:"name"`
+	Action  string `db:"action" json:"action"`
+	Actor   string `db:"actor" json:"actor"`
+	Date    int64  `db:"date" json:"date"`
+	Hash    string `db:"hash" json:"hash"`
+}
+
+// State of migrations in a directory, compared to the migration log
+type MigrationStatus struct {
+	Applied []MigrationLog `json:"applied"`
+	Pending []Migration    `json:"pending"`
+	// Applied migrations, whose query on disk differs from the one executed
+	QueryDrifted []MigrationLog `json:"queryDrifted"`
+	// Applied migrations, whose rollback on disk differs from the one stored in migration log
+	RollbackDrifted []MigrationLog `json:"rollbackDrifted"`
+}
+
+// Progress of a batched backfill. LastKey is the largest key processed so far
+type BackfillCheckpoint struct {
+	Name    string `db:"name" json:"name"`
+	LastKey int64  `db:"last_key" json:"lastKey"`
+	Rows    int64  `db:"row_count" json:"rows"`
+	Date    int64  `db:"date" json:"date"`
+}
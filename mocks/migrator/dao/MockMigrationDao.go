@@ -37,6 +37,14 @@ type MockMigrationDao_Expecter struct {
 
 var passThroughMap = map[string]func(_mock *MockMigrationDao){
 
+	"CheckPrecondition": func(mockMigrationDao *MockMigrationDao) {
+		mockMigrationDao.EXPECT().CheckPrecondition(
+			mock.Anything,
+			mock.Anything,
+		).RunAndReturn(func(tx *sqlx.Tx, query string) (b bool, err error) {
+			return mockMigrationDao.orig.CheckPrecondition(tx, query)
+		}).Once()
+	},
 	"DeleteMigrationLog": func(mockMigrationDao *MockMigrationDao) {
 		mockMigrationDao.EXPECT().DeleteMigrationLog(
 			mock.Anything,
@@ -45,6 +53,16 @@ var passThroughMap = map[string]func(_mock *MockMigrationDao){
 			return mockMigrationDao.orig.DeleteMigrationLog(tx, mLog)
 		}).Once()
 	},
+	"ExecuteBatch": func(mockMigrationDao *MockMigrationDao) {
+		mockMigrationDao.EXPECT().ExecuteBatch(
+			mock.Anything,
+			mock.Anything,
+			mock.Anything,
+			mock.Anything,
+		).RunAndReturn(func(tx *sqlx.Tx, query string, after int64, end int64) (n int64, err error) {
+			return mockMigrationDao.orig.ExecuteBatch(tx, query, after, end)
+		}).Once()
+	},
 	"ExecuteQuery": func(mockMigrationDao *MockMigrationDao) {
 		mockMigrationDao.EXPECT().ExecuteQuery(
 			mock.Anything,
@@ -61,6 +79,33 @@ var passThroughMap = map[string]func(_mock *MockMigrationDao){
 			return mockMigrationDao.orig.ExecuteRollback(tx, m)
 		}).Once()
 	},
+	"GetBackfillCheckpoint": func(mockMigrationDao *MockMigrationDao) {
+		mockMigrationDao.EXPECT().GetBackfillCheckpoint(
+			mock.Anything,
+			mock.Anything,
+		).RunAndReturn(func(tx *sqlx.Tx, name string) (backfillCheckpoint *types.BackfillCheckpoint, err error) {
+			return mockMigrationDao.orig.GetBackfillCheckpoint(tx, name)
+		}).Once()
+	},
+	"GetBatchEnd": func(mockMigrationDao *MockMigrationDao) {
+		mockMigrationDao.EXPECT().GetBatchEnd(
+			mock.Anything,
+			mock.Anything,
+			mock.Anything,
+			mock.Anything,
+			mock.Anything,
+		).RunAndReturn(func(tx *sqlx.Tx, table string, keyColumn string, after int64, size int) (n *int64, err error) {
+			return mockMigrationDao.orig.GetBatchEnd(tx, table, keyColumn, after, size)
+		}).Once()
+	},
+	"GetMigrationHistory": func(mockMigrationDao *MockMigrationDao) {
+		mockMigrationDao.EXPECT().GetMigrationHistory(
+			mock.Anything,
+			mock.Anything,
+		).RunAndReturn(func(tx *sqlx.Tx, version string) (migrationHistorys []types.MigrationHistory, err error) {
+			return mockMigrationDao.orig.GetMigrationHistory(tx, version)
+		}).Once()
+	},
 	"GetMigrationLogs": func(mockMigrationDao *MockMigrationDao) {
 		mockMigrationDao.EXPECT().GetMigrationLogs(
 			mock.Anything,
@@ -68,6 +113,14 @@ var passThroughMap = map[string]func(_mock *MockMigrationDao){
 			return mockMigrationDao.orig.GetMigrationLogs(tx)
 		}).Once()
 	},
+	"InsertMigrationHistory": func(mockMigrationDao *MockMigrationDao) {
+		mockMigrationDao.EXPECT().InsertMigrationHistory(
+			mock.Anything,
+			mock.Anything,
+		).RunAndReturn(func(tx *sqlx.Tx, h types.MigrationHistory) (err error) {
+			return mockMigrationDao.orig.InsertMigrationHistory(tx, h)
+		}).Once()
+	},
 	"InsertMigrationLog": func(mockMigrationDao *MockMigrationDao) {
 		mockMigrationDao.EXPECT().InsertMigrationLog(
 			mock.Anything,
@@ -76,6 +129,14 @@ var passThroughMap = map[string]func(_mock *MockMigrationDao){
 			return mockMigrationDao.orig.InsertMigrationLog(tx, mLog)
 		}).Once()
 	},
+	"SaveBackfillCheckpoint": func(mockMigrationDao *MockMigrationDao) {
+		mockMigrationDao.EXPECT().SaveBackfillCheckpoint(
+			mock.Anything,
+			mock.Anything,
+		).RunAndReturn(func(tx *sqlx.Tx, c types.BackfillCheckpoint) (err error) {
+			return mockMigrationDao.orig.SaveBackfillCheckpoint(tx, c)
+		}).Once()
+	},
 	"SetupMigrationTable": func(mockMigrationDao *MockMigrationDao) {
 		mockMigrationDao.EXPECT().SetupMigrationTable(
 			mock.Anything,
@@ -83,6 +144,14 @@ var passThroughMap = map[string]func(_mock *MockMigrationDao){
 			return mockMigrationDao.orig.SetupMigrationTable(tx)
 		}).Once()
 	},
+	"UpdateMigrationLog": func(mockMigrationDao *MockMigrationDao) {
+		mockMigrationDao.EXPECT().UpdateMigrationLog(
+			mock.Anything,
+			mock.Anything,
+		).RunAndReturn(func(tx *sqlx.Tx, mLog types.MigrationLog) (err error) {
+			return mockMigrationDao.orig.UpdateMigrationLog(tx, mLog)
+		}).Once()
+	},
 }
 
 func (_mock *MockMigrationDao) PassThrough(methodNames ...string) {
@@ -98,6 +167,72 @@ func (_m *MockMigrationDao) EXPECT() *MockMigrationDao_Expecter {
 	return &MockMigrationDao_Expecter{mock: &_m.Mock}
 }
 
+// CheckPrecondition provides a mock function for the type MockMigrationDao
+func (_mock *MockMigrationDao) CheckPrecondition(tx *sqlx.Tx, query string) (bool, error) {
+	ret := _mock.Called(tx, query)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CheckPrecondition")
+	}
+
+	var r0 bool
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(*sqlx.Tx, string) (bool, error)); ok {
+		return returnFunc(tx, query)
+	}
+	if returnFunc, ok := ret.Get(0).(func(*sqlx.Tx, string) bool); ok {
+		r0 = returnFunc(tx, query)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+	if returnFunc, ok := ret.Get(1).(func(*sqlx.Tx, string) error); ok {
+		r1 = returnFunc(tx, query)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockMigrationDao_CheckPrecondition_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CheckPrecondition'
+type MockMigrationDao_CheckPrecondition_Call struct {
+	*mock.Call
+}
+
+// CheckPrecondition is a helper method to define mock.On call
+//   - tx *sqlx.Tx
+//   - query string
+func (_e *MockMigrationDao_Expecter) CheckPrecondition(tx interface{}, query interface{}) *MockMigrationDao_CheckPrecondition_Call {
+	return &MockMigrationDao_CheckPrecondition_Call{Call: _e.mock.On("CheckPrecondition", tx, query)}
+}
+
+func (_c *MockMigrationDao_CheckPrecondition_Call) Run(run func(tx *sqlx.Tx, query string)) *MockMigrationDao_CheckPrecondition_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 *sqlx.Tx
+		if args[0] != nil {
+			arg0 = args[0].(*sqlx.Tx)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockMigrationDao_CheckPrecondition_Call) Return(b bool, err error) *MockMigrationDao_CheckPrecondition_Call {
+	_c.Call.Return(b, err)
+	return _c
+}
+
+func (_c *MockMigrationDao_CheckPrecondition_Call) RunAndReturn(run func(tx *sqlx.Tx, query string) (bool, error)) *MockMigrationDao_CheckPrecondition_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // DeleteMigrationLog provides a mock function for the type MockMigrationDao
 func (_mock *MockMigrationDao) DeleteMigrationLog(tx *sqlx.Tx, mLog types.MigrationLog) error {
 	ret := _mock.Called(tx, mLog)
@@ -155,6 +290,84 @@ func (_c *MockMigrationDao_DeleteMigrationLog_Call) RunAndReturn(run func(tx *sq
 	return _c
 }
 
+// ExecuteBatch provides a mock function for the type MockMigrationDao
+func (_mock *MockMigrationDao) ExecuteBatch(tx *sqlx.Tx, query string, after int64, end int64) (int64, error) {
+	ret := _mock.Called(tx, query, after, end)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ExecuteBatch")
+	}
+
+	var r0 int64
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(*sqlx.Tx, string, int64, int64) (int64, error)); ok {
+		return returnFunc(tx, query, after, end)
+	}
+	if returnFunc, ok := ret.Get(0).(func(*sqlx.Tx, string, int64, int64) int64); ok {
+		r0 = returnFunc(tx, query, after, end)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+	if returnFunc, ok := ret.Get(1).(func(*sqlx.Tx, string, int64, int64) error); ok {
+		r1 = returnFunc(tx, query, after, end)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockMigrationDao_ExecuteBatch_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ExecuteBatch'
+type MockMigrationDao_ExecuteBatch_Call struct {
+	*mock.Call
+}
+
+// ExecuteBatch is a helper method to define mock.On call
+//   - tx *sqlx.Tx
+//   - query string
+//   - after int64
+//   - end int64
+func (_e *MockMigrationDao_Expecter) ExecuteBatch(tx interface{}, query interface{}, after interface{}, end interface{}) *MockMigrationDao_ExecuteBatch_Call {
+	return &MockMigrationDao_ExecuteBatch_Call{Call: _e.mock.On("ExecuteBatch", tx, query, after, end)}
+}
+
+func (_c *MockMigrationDao_ExecuteBatch_Call) Run(run func(tx *sqlx.Tx, query string, after int64, end int64)) *MockMigrationDao_ExecuteBatch_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 *sqlx.Tx
+		if args[0] != nil {
+			arg0 = args[0].(*sqlx.Tx)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 int64
+		if args[2] != nil {
+			arg2 = args[2].(int64)
+		}
+		var arg3 int64
+		if args[3] != nil {
+			arg3 = args[3].(int64)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+		)
+	})
+	return _c
+}
+
+func (_c *MockMigrationDao_ExecuteBatch_Call) Return(n int64, err error) *MockMigrationDao_ExecuteBatch_Call {
+	_c.Call.Return(n, err)
+	return _c
+}
+
+func (_c *MockMigrationDao_ExecuteBatch_Call) RunAndReturn(run func(tx *sqlx.Tx, query string, after int64, end int64) (int64, error)) *MockMigrationDao_ExecuteBatch_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // ExecuteQuery provides a mock function for the type MockMigrationDao
 func (_mock *MockMigrationDao) ExecuteQuery(tx *sqlx.Tx, m types.Migration) error {
 	ret := _mock.Called(tx, m)
@@ -269,6 +482,228 @@ func (_c *MockMigrationDao_ExecuteRollback_Call) RunAndReturn(run func(tx *sqlx.
 	return _c
 }
 
+// GetBackfillCheckpoint provides a mock function for the type MockMigrationDao
+func (_mock *MockMigrationDao) GetBackfillCheckpoint(tx *sqlx.Tx, name string) (*types.BackfillCheckpoint, error) {
+	ret := _mock.Called(tx, name)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetBackfillCheckpoint")
+	}
+
+	var r0 *types.BackfillCheckpoint
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(*sqlx.Tx, string) (*types.BackfillCheckpoint, error)); ok {
+		return returnFunc(tx, name)
+	}
+	if returnFunc, ok := ret.Get(0).(func(*sqlx.Tx, string) *types.BackfillCheckpoint); ok {
+		r0 = returnFunc(tx, name)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*types.BackfillCheckpoint)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(*sqlx.Tx, string) error); ok {
+		r1 = returnFunc(tx, name)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockMigrationDao_GetBackfillCheckpoint_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetBackfillCheckpoint'
+type MockMigrationDao_GetBackfillCheckpoint_Call struct {
+	*mock.Call
+}
+
+// GetBackfillCheckpoint is a helper method to define mock.On call
+//   - tx *sqlx.Tx
+//   - name string
+func (_e *MockMigrationDao_Expecter) GetBackfillCheckpoint(tx interface{}, name interface{}) *MockMigrationDao_GetBackfillCheckpoint_Call {
+	return &MockMigrationDao_GetBackfillCheckpoint_Call{Call: _e.mock.On("GetBackfillCheckpoint", tx, name)}
+}
+
+func (_c *MockMigrationDao_GetBackfillCheckpoint_Call) Run(run func(tx *sqlx.Tx, name string)) *MockMigrationDao_GetBackfillCheckpoint_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 *sqlx.Tx
+		if args[0] != nil {
+			arg0 = args[0].(*sqlx.Tx)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockMigrationDao_GetBackfillCheckpoint_Call) Return(backfillCheckpoint *types.BackfillCheckpoint, err error) *MockMigrationDao_GetBackfillCheckpoint_Call {
+	_c.Call.Return(backfillCheckpoint, err)
+	return _c
+}
+
+func (_c *MockMigrationDao_GetBackfillCheckpoint_Call) RunAndReturn(run func(tx *sqlx.Tx, name string) (*types.BackfillCheckpoint, error)) *MockMigrationDao_GetBackfillCheckpoint_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetBatchEnd provides a mock function for the type MockMigrationDao
+func (_mock *MockMigrationDao) GetBatchEnd(tx *sqlx.Tx, table string, keyColumn string, after int64, size int) (*int64, error) {
+	ret := _mock.Called(tx, table, keyColumn, after, size)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetBatchEnd")
+	}
+
+	var r0 *int64
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(*sqlx.Tx, string, string, int64, int) (*int64, error)); ok {
+		return returnFunc(tx, table, keyColumn, after, size)
+	}
+	if returnFunc, ok := ret.Get(0).(func(*sqlx.Tx, string, string, int64, int) *int64); ok {
+		r0 = returnFunc(tx, table, keyColumn, after, size)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*int64)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(*sqlx.Tx, string, string, int64, int) error); ok {
+		r1 = returnFunc(tx, table, keyColumn, after, size)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockMigrationDao_GetBatchEnd_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetBatchEnd'
+type MockMigrationDao_GetBatchEnd_Call struct {
+	*mock.Call
+}
+
+// GetBatchEnd is a helper method to define mock.On call
+//   - tx *sqlx.Tx
+//   - table string
+//   - keyColumn string
+//   - after int64
+//   - size int
+func (_e *MockMigrationDao_Expecter) GetBatchEnd(tx interface{}, table interface{}, keyColumn interface{}, after interface{}, size interface{}) *MockMigrationDao_GetBatchEnd_Call {
+	return &MockMigrationDao_GetBatchEnd_Call{Call: _e.mock.On("GetBatchEnd", tx, table, keyColumn, after, size)}
+}
+
+func (_c *MockMigrationDao_GetBatchEnd_Call) Run(run func(tx *sqlx.Tx, table string, keyColumn string, after int64, size int)) *MockMigrationDao_GetBatchEnd_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 *sqlx.Tx
+		if args[0] != nil {
+			arg0 = args[0].(*sqlx.Tx)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 string
+		if args[2] != nil {
+			arg2 = args[2].(string)
+		}
+		var arg3 int64
+		if args[3] != nil {
+			arg3 = args[3].(int64)
+		}
+		var arg4 int
+		if args[4] != nil {
+			arg4 = args[4].(int)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+			arg4,
+		)
+	})
+	return _c
+}
+
+func (_c *MockMigrationDao_GetBatchEnd_Call) Return(n *int64, err error) *MockMigrationDao_GetBatchEnd_Call {
+	_c.Call.Return(n, err)
+	return _c
+}
+
+func (_c *MockMigrationDao_GetBatchEnd_Call) RunAndReturn(run func(tx *sqlx.Tx, table string, keyColumn string, after int64, size int) (*int64, error)) *MockMigrationDao_GetBatchEnd_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetMigrationHistory provides a mock function for the type MockMigrationDao
+func (_mock *MockMigrationDao) GetMigrationHistory(tx *sqlx.Tx, version string) ([]types.MigrationHistory, error) {
+	ret := _mock.Called(tx, version)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetMigrationHistory")
+	}
+
+	var r0 []types.MigrationHistory
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(*sqlx.Tx, string) ([]types.MigrationHistory, error)); ok {
+		return returnFunc(tx, version)
+	}
+	if returnFunc, ok := ret.Get(0).(func(*sqlx.Tx, string) []types.MigrationHistory); ok {
+		r0 = returnFunc(tx, version)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]types.MigrationHistory)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(*sqlx.Tx, string) error); ok {
+		r1 = returnFunc(tx, version)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockMigrationDao_GetMigrationHistory_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetMigrationHistory'
+type MockMigrationDao_GetMigrationHistory_Call struct {
+	*mock.Call
+}
+
+// GetMigrationHistory is a helper method to define mock.On call
+//   - tx *sqlx.Tx
+//   - version string
+func (_e *MockMigrationDao_Expecter) GetMigrationHistory(tx interface{}, version interface{}) *MockMigrationDao_GetMigrationHistory_Call {
+	return &MockMigrationDao_GetMigrationHistory_Call{Call: _e.mock.On("GetMigrationHistory", tx, version)}
+}
+
+func (_c *MockMigrationDao_GetMigrationHistory_Call) Run(run func(tx *sqlx.Tx, version string)) *MockMigrationDao_GetMigrationHistory_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 *sqlx.Tx
+		if args[0] != nil {
+			arg0 = args[0].(*sqlx.Tx)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockMigrationDao_GetMigrationHistory_Call) Return(migrationHistorys []types.MigrationHistory, err error) *MockMigrationDao_GetMigrationHistory_Call {
+	_c.Call.Return(migrationHistorys, err)
+	return _c
+}
+
+func (_c *MockMigrationDao_GetMigrationHistory_Call) RunAndReturn(run func(tx *sqlx.Tx, version string) ([]types.MigrationHistory, error)) *MockMigrationDao_GetMigrationHistory_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // GetMigrationLogs provides a mock function for the type MockMigrationDao
 func (_mock *MockMigrationDao) GetMigrationLogs(tx *sqlx.Tx) ([]types.MigrationLog, error) {
 	ret := _mock.Called(tx)
@@ -331,6 +766,63 @@ func (_c *MockMigrationDao_GetMigrationLogs_Call) RunAndReturn(run func(tx *sqlx
 	return _c
 }
 
+// InsertMigrationHistory provides a mock function for the type MockMigrationDao
+func (_mock *MockMigrationDao) InsertMigrationHistory(tx *sqlx.Tx, h types.MigrationHistory) error {
+	ret := _mock.Called(tx, h)
+
+	if len(ret) == 0 {
+		panic("no return value specified for InsertMigrationHistory")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(*sqlx.Tx, types.MigrationHistory) error); ok {
+		r0 = returnFunc(tx, h)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockMigrationDao_InsertMigrationHistory_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'InsertMigrationHistory'
+type MockMigrationDao_InsertMigrationHistory_Call struct {
+	*mock.Call
+}
+
+// InsertMigrationHistory is a helper method to define mock.On call
+//   - tx *sqlx.Tx
+//   - h types.MigrationHistory
+func (_e *MockMigrationDao_Expecter) InsertMigrationHistory(tx interface{}, h interface{}) *MockMigrationDao_InsertMigrationHistory_Call {
+	return &MockMigrationDao_InsertMigrationHistory_Call{Call: _e.mock.On("InsertMigrationHistory", tx, h)}
+}
+
+func (_c *MockMigrationDao_InsertMigrationHistory_Call) Run(run func(tx *sqlx.Tx, h types.MigrationHistory)) *MockMigrationDao_InsertMigrationHistory_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 *sqlx.Tx
+		if args[0] != nil {
+			arg0 = args[0].(*sqlx.Tx)
+		}
+		var arg1 types.MigrationHistory
+		if args[1] != nil {
+			arg1 = args[1].(types.MigrationHistory)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockMigrationDao_InsertMigrationHistory_Call) Return(err error) *MockMigrationDao_InsertMigrationHistory_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockMigrationDao_InsertMigrationHistory_Call) RunAndReturn(run func(tx *sqlx.Tx, h types.MigrationHistory) error) *MockMigrationDao_InsertMigrationHistory_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // InsertMigrationLog provides a mock function for the type MockMigrationDao
 func (_mock *MockMigrationDao) InsertMigrationLog(tx *sqlx.Tx, mLog types.MigrationLog) error {
 	ret := _mock.Called(tx, mLog)
@@ -388,6 +880,63 @@ func (_c *MockMigrationDao_InsertMigrationLog_Call) RunAndReturn(run func(tx *sq
 	return _c
 }
 
+// SaveBackfillCheckpoint provides a mock function for the type MockMigrationDao
+func (_mock *MockMigrationDao) SaveBackfillCheckpoint(tx *sqlx.Tx, c types.BackfillCheckpoint) error {
+	ret := _mock.Called(tx, c)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SaveBackfillCheckpoint")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(*sqlx.Tx, types.BackfillCheckpoint) error); ok {
+		r0 = returnFunc(tx, c)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockMigrationDao_SaveBackfillCheckpoint_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SaveBackfillCheckpoint'
+type MockMigrationDao_SaveBackfillCheckpoint_Call struct {
+	*mock.Call
+}
+
+// SaveBackfillCheckpoint is a helper method to define mock.On call
+//   - tx *sqlx.Tx
+//   - c types.BackfillCheckpoint
+func (_e *MockMigrationDao_Expecter) SaveBackfillCheckpoint(tx interface{}, c interface{}) *MockMigrationDao_SaveBackfillCheckpoint_Call {
+	return &MockMigrationDao_SaveBackfillCheckpoint_Call{Call: _e.mock.On("SaveBackfillCheckpoint", tx, c)}
+}
+
+func (_c *MockMigrationDao_SaveBackfillCheckpoint_Call) Run(run func(tx *sqlx.Tx, c types.BackfillCheckpoint)) *MockMigrationDao_SaveBackfillCheckpoint_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 *sqlx.Tx
+		if args[0] != nil {
+			arg0 = args[0].(*sqlx.Tx)
+		}
+		var arg1 types.BackfillCheckpoint
+		if args[1] != nil {
+			arg1 = args[1].(types.BackfillCheckpoint)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockMigrationDao_SaveBackfillCheckpoint_Call) Return(err error) *MockMigrationDao_SaveBackfillCheckpoint_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockMigrationDao_SaveBackfillCheckpoint_Call) RunAndReturn(run func(tx *sqlx.Tx, c types.BackfillCheckpoint) error) *MockMigrationDao_SaveBackfillCheckpoint_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // SetupMigrationTable provides a mock function for the type MockMigrationDao
 func (_mock *MockMigrationDao) SetupMigrationTable(tx *sqlx.Tx) error {
 	ret := _mock.Called(tx)
@@ -438,3 +987,60 @@ func (_c *MockMigrationDao_SetupMigrationTable_Call) RunAndReturn(run func(tx *s
 	_c.Call.Return(run)
 	return _c
 }
+
+// UpdateMigrationLog provides a mock function for the type MockMigrationDao
+func (_mock *MockMigrationDao) UpdateMigrationLog(tx *sqlx.Tx, mLog types.MigrationLog) error {
+	ret := _mock.Called(tx, mLog)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateMigrationLog")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(*sqlx.Tx, types.MigrationLog) error); ok {
+		r0 = returnFunc(tx, mLog)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockMigrationDao_UpdateMigrationLog_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateMigrationLog'
+type MockMigrationDao_UpdateMigrationLog_Call struct {
+	*mock.Call
+}
+
+// UpdateMigrationLog is a helper method to define mock.On call
+//   - tx *sqlx.Tx
+//   - mLog types.MigrationLog
+func (_e *MockMigrationDao_Expecter) UpdateMigrationLog(tx interface{}, mLog interface{}) *MockMigrationDao_UpdateMigrationLog_Call {
+	return &MockMigrationDao_UpdateMigrationLog_Call{Call: _e.mock.On("UpdateMigrationLog", tx, mLog)}
+}
+
+func (_c *MockMigrationDao_UpdateMigrationLog_Call) Run(run func(tx *sqlx.Tx, mLog types.MigrationLog)) *MockMigrationDao_UpdateMigrationLog_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 *sqlx.Tx
+		if args[0] != nil {
+			arg0 = args[0].(*sqlx.Tx)
+		}
+		var arg1 types.MigrationLog
+		if args[1] != nil {
+			arg1 = args[1].(types.MigrationLog)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockMigrationDao_UpdateMigrationLog_Call) Return(err error) *MockMigrationDao_UpdateMigrationLog_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockMigrationDao_UpdateMigrationLog_Call) RunAndReturn(run func(tx *sqlx.Tx, mLog types.MigrationLog) error) *MockMigrationDao_UpdateMigrationLog_Call {
+	_c.Call.Return(run)
+	return _c
+}
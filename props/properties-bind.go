@@ -0,0 +1,188 @@
+package props
+
+import (
+	"encoding"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"time"
+)
+
+const (
+	TAG_PROP     = "prop"
+	TAG_DEFAULT  = "default"
+	TAG_REQUIRED = "required"
+)
+
+var durationType = reflect.TypeFor[time.Duration]()
+var textUnmarshalerType = reflect.TypeFor[encoding.TextUnmarshaler]()
+
+// Binds values, like the ones returned by ReadFiles, into target. See Properties.Bind
+func Bind(values map[string]string, target any) error {
+	p := New()
+	for key, value := range values {
+		p.Set(key, value, "properties")
+	}
+	return p.Bind(target)
+}
+
+// Sets fields of the struct pointed by target, from properties named by their 'prop' tag, e.g.
+//
+//	type DBConfig struct {
+//		URL     string        `prop:"url" required:"true"`
+//		Pool    int           `prop:"pool" default:"10"`
+//		Timeout time.Duration `prop:"timeout" default:"5s"`
+//		Hosts   []string      `prop:"hosts"`
+//	}
+//	type Config struct {
+//		DB DBConfig `prop:"db"`
+//	}
+//
+// Tag of a struct field is the key prefix of its fields, e.g. db.url. Untagged struct fields use the parent prefix.
+// Supported field types are string, bool, ints, uints, floats, time.Duration, map[string]string,
+// encoding.TextUnmarshaler implementations, and slices of these, which are comma separated like StringSlice.
+// Missing keys use the 'default' tag, else fail for 'required:"true"' fields, else leave the field unchanged.
+// All binding errors are reported together
+func (p *Properties) Bind(target any) error {
+	v := reflect.ValueOf(target)
+	if v.Kind() != reflect.Pointer || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("bind target has to be a non nil pointer to struct, found %T", target)
+	}
+	errs := p.bindStruct(v.Elem(), "")
+	if len(errs) > 0 {
+		return fmt.Errorf("error while binding properties to %v\n%w", v.Elem().Type(), errors.Join(errs...))
+	}
+	return nil
+}
+
+func (p *Properties) bindStruct(v reflect.Value, prefix string) []error {
+	errs := []error{}
+	t := v.Type()
+	for i := range t.NumField() {
+		field := t.Field(i)
+		tag, tagged := field.Tag.Lookup(TAG_PROP)
+		if !field.IsExported() || tag == "-" {
+			continue
+		}
+		fv := v.Field(i)
+		if isNested(field.Type) {
+			nestedPrefix := prefix
+			if tag != "" {
+				nestedPrefix = prefix + tag + "."
+			}
+			errs = append(errs, p.bindStruct(fv, nestedPrefix)...)
+			continue
+		}
+		if !tagged || tag == "" {
+			continue
+		}
+		fieldName := field.Name
+		if t.Name() != "" {
+			fieldName = t.Name() + "." + field.Name
+		}
+		if err := p.bindField(fv, fieldName, field, prefix+tag); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errs
+}
+
+// Struct fields, which are not set from a single value
+func isNested(t reflect.Type) bool {
+	return t.Kind() == reflect.Struct && !reflect.PointerTo(t).Implements(textUnmarshalerType)
+}
+
+func (p *Properties) bindField(fv reflect.Value, fieldName string, field reflect.StructField, key string) error {
+	if value, exists := p.values[key]; exists {
+		if err := setValue(fv, value); err != nil {
+			return fmt.Errorf("%w %v='%v' in %v, expected %v for field %v\n%w", ErrInvalidProperty, key, value, p.sources[key], field.Type, fieldName, err)
+		}
+		return nil
+	}
+	if def, hasDefault := field.Tag.Lookup(TAG_DEFAULT); hasDefault {
+		if err := setValue(fv, def); err != nil {
+			return fmt.Errorf("invalid default '%v' of field %v for property %v, expected %v\n%w", def, fieldName, key, field.Type, err)
+		}
+		return nil
+	}
+	if required, _ := strconv.ParseBool(field.Tag.Get(TAG_REQUIRED)); required {
+		return fmt.Errorf("%w %v, required by field %v", ErrMissingProperty, key, fieldName)
+	}
+	return nil
+}
+
+func setValue(fv reflect.Value, value string) error {
+	if fv.Addr().Type().Implements(textUnmarshalerType) {
+		return fv.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(value))
+	}
+	if fv.Type() == durationType {
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return err
+		}
+		fv.SetInt(int64(d))
+		return nil
+	}
+	switch fv.Kind() {
+	case reflect.String:
+		fv.SetString(value)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return err
+		}
+		fv.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(value, 10, fv.Type().Bits())
+		if err != nil {
+			return err
+		}
+		fv.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(value, 10, fv.Type().Bits())
+		if err != nil {
+			return err
+		}
+		fv.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(value, fv.Type().Bits())
+		if err != nil {
+			return err
+		}
+		fv.SetFloat(f)
+	case reflect.Slice:
+		return setSlice(fv, value)
+	case reflect.Map:
+		if fv.Type().Key().Kind() != reflect.String || fv.Type().Elem().Kind() != reflect.String {
+			return fmt.Errorf("unsupported type %v", fv.Type())
+		}
+		entries, err := parseMap(value)
+		if err != nil {
+			return err
+		}
+		m := reflect.MakeMapWithSize(fv.Type(), len(entries))
+		for k, v := range entries {
+			m.SetMapIndex(reflect.ValueOf(k).Convert(fv.Type().Key()), reflect.ValueOf(v).Convert(fv.Type().Elem()))
+		}
+		fv.Set(m)
+	default:
+		return fmt.Errorf("unsupported type %v", fv.Type())
+	}
+	return nil
+}
+
+func setSlice(fv reflect.Value, value string) error {
+	if elemType := fv.Type().Elem(); elemType.Kind() == reflect.Slice && !reflect.PointerTo(elemType).Implements(textUnmarshalerType) {
+		return fmt.Errorf("unsupported type %v", fv.Type())
+	}
+	elems, _ := parseStringSlice(value)
+	slice := reflect.MakeSlice(fv.Type(), len(elems), len(elems))
+	for i, elem := range elems {
+		if err := setValue(slice.Index(i), elem); err != nil {
+			return fmt.Errorf("error in element %v '%v'\n%w", i, elem, err)
+		}
+	}
+	fv.Set(slice)
+	return nil
+}
@@ -0,0 +1,160 @@
+package props
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wizards-0/go-pins/semver"
+)
+
+type DBConfig struct {
+	URL     string            `prop:"url" required:"true"`
+	Pool    int               `prop:"pool" default:"10"`
+	Timeout time.Duration     `prop:"timeout" default:"5s"`
+	Hosts   []string          `prop:"hosts"`
+	Params  map[string]string `prop:"params"`
+}
+
+type ServerConfig struct {
+	Port    uint16          `prop:"port" default:"8080"`
+	Debug   bool            `prop:"debug"`
+	Ratio   float64         `prop:"ratio"`
+	Weights []float32       `prop:"weights"`
+	Delays  []time.Duration `prop:"delays"`
+}
+
+type AppConfig struct {
+	Name     string         `prop:"app.name"`
+	Version  semver.Version `prop:"app.version"`
+	DB       DBConfig       `prop:"db"`
+	Replica  DBConfig       `prop:"db.replica"`
+	Server   ServerConfig
+	Ignored  string `prop:"-"`
+	Untagged string
+	internal string `prop:"app.name"`
+}
+
+func TestBind(t *testing.T) {
+	assert := assert.New(t)
+	p := propsOf(map[string]string{
+		"app.name":           "orders",
+		"app.version":        "v1.4.0-rc.1",
+		"db.url":             "postgres://db/orders",
+		"db.pool":            "25",
+		"db.hosts":           "db1, db2",
+		"db.params":          "sslmode=disable",
+		"db.replica.url":     "postgres://replica/orders",
+		"db.replica.hosts":   "",
+		"db.replica.timeout": "1m",
+		"debug":              "true",
+		"ratio":              "0.5",
+		"weights":            "1.5,2",
+		"delays":             "1s, 250ms",
+		"Ignored":            "x",
+		"Untagged":           "x",
+	})
+	cfg := AppConfig{Ignored: "kept", Untagged: "kept"}
+	assert.Nil(p.Bind(&cfg))
+	assert.Equal(AppConfig{
+		Name:    "orders",
+		Version: semver.MustParse("1.4.0-rc.1"),
+		DB: DBConfig{
+			URL:     "postgres://db/orders",
+			Pool:    25,
+			Timeout: 5 * time.Second,
+			Hosts:   []string{"db1", "db2"},
+			Params:  map[string]string{"sslmode": "disable"},
+		},
+		Replica: DBConfig{
+			URL:     "postgres://replica/orders",
+			Pool:    10,
+			Timeout: time.Minute,
+			Hosts:   []string{},
+		},
+		Server: ServerConfig{
+			Port:    8080,
+			Debug:   true,
+			Ratio:   0.5,
+			Weights: []float32{1.5, 2},
+			Delays:  []time.Duration{time.Second, 250 * time.Millisecond},
+		},
+		Ignored:  "kept",
+		Untagged: "kept",
+	}, cfg)
+}
+
+func TestBindMap(t *testing.T) {
+	assert := assert.New(t)
+	values, err := ReadFiles(TYPED_PATH)
+	assert.Nil(err)
+	cfg := struct {
+		Port    int           `prop:"server.port"`
+		Timeout time.Duration `prop:"server.timeout"`
+		Hosts   []string      `prop:"server.hosts"`
+	}{}
+	assert.Nil(Bind(values, &cfg))
+	assert.Equal(8080, cfg.Port)
+	assert.Equal(90*time.Second, cfg.Timeout)
+	assert.Equal([]string{"a.example.com", "b.example.com"}, cfg.Hosts)
+
+	err = Bind(map[string]string{"server.port": "http"}, &cfg)
+	assert.ErrorContains(err, "invalid property server.port='http' in properties, expected int for field Port")
+}
+
+func TestBindErrors(t *testing.T) {
+	assert := assert.New(t)
+	p := propsOf(map[string]string{
+		"db.pool":        "many",
+		"db.timeout":     "5",
+		"db.replica.url": "postgres://replica/orders",
+		"app.version":    "1.4",
+		"port":           "70000",
+		"weights":        "1,x",
+		"db.params":      "sslmode",
+	})
+	cfg := AppConfig{}
+	err := p.Bind(&cfg)
+	assert.ErrorIs(err, ErrInvalidProperty)
+	assert.ErrorIs(err, ErrMissingProperty)
+	assert.ErrorIs(err, semver.ErrInvalidVersion)
+	for _, msg := range []string{
+		"error while binding properties to props.AppConfig",
+		"invalid property app.version='1.4' in app.properties, expected semver.Version for field AppConfig.Version",
+		"missing property db.url, required by field DBConfig.URL",
+		"invalid property db.pool='many' in app.properties, expected int for field DBConfig.Pool",
+		"invalid property db.timeout='5' in app.properties, expected time.Duration for field DBConfig.Timeout",
+		"invalid property db.params='sslmode' in app.properties, expected map[string]string for field DBConfig.Params",
+		"invalid property port='70000' in app.properties, expected uint16 for field ServerConfig.Port",
+		"invalid property weights='1,x' in app.properties, expected []float32 for field ServerConfig.Weights\nerror in element 1 'x'",
+	} {
+		assert.ErrorContains(err, msg)
+	}
+	assert.NotContains(err.Error(), "db.replica.url")
+
+	invalid := struct {
+		Pool    int            `prop:"pool" default:"ten"`
+		Nested  [][]int        `prop:"nested"`
+		Ptr     *int           `prop:"ptr"`
+		Counts  map[string]int `prop:"counts"`
+		Skipped *int           `prop:"skipped"`
+	}{}
+	err = propsOf(map[string]string{"nested": "1", "ptr": "1", "counts": "a=1"}).Bind(&invalid)
+	assert.ErrorContains(err, "invalid default 'ten' of field Pool for property pool, expected int")
+	assert.ErrorContains(err, "expected [][]int for field Nested\nunsupported type [][]int")
+	assert.ErrorContains(err, "expected *int for field Ptr\nunsupported type *int")
+	assert.ErrorContains(err, "expected map[string]int for field Counts\nunsupported type map[string]int")
+
+	type paramKey string
+	type paramValue string
+	named := struct {
+		Params map[paramKey]paramValue `prop:"params"`
+	}{}
+	assert.Nil(propsOf(map[string]string{"params": "sslmode=disable"}).Bind(&named))
+	assert.Equal(map[paramKey]paramValue{"sslmode": "disable"}, named.Params)
+
+	assert.EqualError(p.Bind(cfg), "bind target has to be a non nil pointer to struct, found props.AppConfig")
+	assert.EqualError(p.Bind((*AppConfig)(nil)), "bind target has to be a non nil pointer to struct, found *props.AppConfig")
+	n := 1
+	assert.EqualError(p.Bind(&n), "bind target has to be a non nil pointer to struct, found *int")
+}
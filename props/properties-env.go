@@ -0,0 +1,43 @@
+package props
+
+import (
+	"os"
+	"strings"
+)
+
+// Maps a property key to the name of the environment variable, which overrides it
+type EnvMapper func(key string) string
+
+// Looks up an environment variable, like os.LookupEnv
+type EnvLookup func(name string) (string, bool)
+
+// Maps keys to upper case, with '.' and '-' replaced by '_', e.g. db.url -> DB_URL, http.read-timeout -> HTTP_READ_TIMEOUT
+func DefaultEnvMapper(key string) string {
+	return strings.ToUpper(strings.NewReplacer(".", "_", "-", "_").Replace(key))
+}
+
+// Maps keys like DefaultEnvMapper, with a prefix, e.g. db.url -> APP_DB_URL for prefix APP_
+func PrefixEnvMapper(prefix string) EnvMapper {
+	return func(key string) string {
+		return prefix + DefaultEnvMapper(key)
+	}
+}
+
+// Overrides values of existing keys with environment variables, e.g. db.url with DB_URL for DefaultEnvMapper.
+// Source of an overridden key is 'environment variable <name>'. Returns the number of overridden keys
+func (p *Properties) OverlayEnv(mapper EnvMapper) int {
+	return p.OverlayLookup(os.LookupEnv, mapper)
+}
+
+// Same as OverlayEnv, with variables from lookup
+func (p *Properties) OverlayLookup(lookup EnvLookup, mapper EnvMapper) int {
+	count := 0
+	for _, key := range p.Keys() {
+		name := mapper(key)
+		if value, exists := lookup(name); exists {
+			p.Set(key, value, "environment variable "+name)
+			count++
+		}
+	}
+	return count
+}
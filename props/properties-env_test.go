@@ -0,0 +1,48 @@
+package props
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func envOf(env map[string]string) EnvLookup {
+	return func(name string) (string, bool) {
+		value, exists := env[name]
+		return value, exists
+	}
+}
+
+func TestEnvMappers(t *testing.T) {
+	assert := assert.New(t)
+	assert.Equal("DB_URL", DefaultEnvMapper("db.url"))
+	assert.Equal("HTTP_READ_TIMEOUT", DefaultEnvMapper("http.read-timeout"))
+	assert.Equal("PORT", DefaultEnvMapper("PORT"))
+	assert.Equal("APP_DB_URL", PrefixEnvMapper("APP_")("db.url"))
+}
+
+func TestOverlayEnv(t *testing.T) {
+	assert := assert.New(t)
+	p := New()
+	p.Set("db.url", "postgres://localhost/app", "app.properties")
+	p.Set("db.user", "app", "app.properties")
+	p.Set("server.port", "8080", "app.properties")
+
+	env := envOf(map[string]string{"DB_URL": "postgres://db/app", "SERVER_PORT": "", "db.user": "ignored"})
+	assert.Equal(2, p.OverlayLookup(env, DefaultEnvMapper))
+	assert.Equal("postgres://db/app", p.MustString("db.url"))
+	assert.Equal("environment variable DB_URL", p.Source("db.url"))
+	assert.Equal("", p.MustString("server.port"))
+	assert.Equal("app", p.MustString("db.user"))
+	assert.Equal("app.properties", p.Source("db.user"))
+
+	_, err := p.GetInt("server.port")
+	assert.ErrorContains(err, "invalid property server.port='' in environment variable SERVER_PORT, expected int")
+
+	assert.Equal(1, p.OverlayLookup(envOf(map[string]string{"APP_DB_USER": "admin"}), PrefixEnvMapper("APP_")))
+	assert.Equal("admin", p.MustString("db.user"))
+
+	t.Setenv("PROPS_TEST_DB_URL", "from-os-env")
+	assert.Equal(1, p.OverlayEnv(PrefixEnvMapper("PROPS_TEST_")))
+	assert.Equal("from-os-env", p.MustString("db.url"))
+}
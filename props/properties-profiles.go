@@ -0,0 +1,82 @@
+package props
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Environment variable with comma separated active profiles, e.g. APP_PROFILES=dev,local
+const PROFILES_ENV = "APP_PROFILES"
+
+const DEFAULT_EXTENSION = ".properties"
+
+type ProfileConfig struct {
+	// Base name of files, e.g. 'app' for app.properties and app-dev.properties
+	BaseName string
+	// Directories to look for files in. Defaults to the working directory
+	SearchPath []string
+	// Active profiles, in increasing order of precedence. Defaults to PROFILES_ENV
+	Profiles []string
+	// Defaults to DEFAULT_EXTENSION
+	Extension string
+}
+
+// Reads <BaseName><Extension>, followed by <BaseName>-<profile><Extension> of each active profile.
+// Each file is read from every directory of search path it exists in, with later directories overriding earlier ones,
+// and later profiles overriding earlier profiles and the base file.
+// Fails if base file, or file of an active profile is not found in any directory
+func LoadProfiles(cfg ProfileConfig) (*Properties, error) {
+	if cfg.BaseName == "" {
+		return nil, errors.New("base name of properties files is required")
+	}
+	if len(cfg.SearchPath) == 0 {
+		cfg.SearchPath = []string{"."}
+	}
+	if cfg.Extension == "" {
+		cfg.Extension = DEFAULT_EXTENSION
+	}
+	profiles := cfg.Profiles
+	if len(profiles) == 0 {
+		profiles = strings.Split(os.Getenv(PROFILES_ENV), ",")
+	}
+
+	p := New()
+	if err := p.readProfile(cfg, ""); err != nil {
+		return nil, err
+	}
+	for _, profile := range profiles {
+		if profile = strings.TrimSpace(profile); profile == "" {
+			continue
+		}
+		if err := p.readProfile(cfg, profile); err != nil {
+			return nil, err
+		}
+	}
+	return p, nil
+}
+
+func (p *Properties) readProfile(cfg ProfileConfig, profile string) error {
+	fileName := cfg.BaseName + cfg.Extension
+	if profile != "" {
+		fileName = cfg.BaseName + "-" + profile + cfg.Extension
+	}
+	found := false
+	for _, dir := range cfg.SearchPath {
+		filePath := filepath.Join(dir, fileName)
+		if _, err := os.Stat(filePath); errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err := p.readFile(filePath, profile); err != nil {
+			return err
+		}
+		found = true
+	}
+	if !found {
+		return fmt.Errorf("properties file %v is not found in search path %v", fileName, cfg.SearchPath)
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+package props
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const PROFILES_PATH = "../resources/test/properties/profiles/"
+
+func TestLoadProfiles(t *testing.T) {
+	assert := assert.New(t)
+	searchPath := []string{PROFILES_PATH + "config", PROFILES_PATH + "override"}
+	p, err := LoadProfiles(ProfileConfig{BaseName: "app", SearchPath: searchPath, Profiles: []string{"dev", " local"}})
+	assert.Nil(err)
+	assert.Equal(map[string]string{
+		"app.name":    "app",
+		"server.port": "9090",
+		"db.url":      "postgres://dev-db/app",
+		"log.level":   "info",
+	}, p.ToMap())
+
+	assert.Equal("", p.Profile("app.name"))
+	assert.Equal("", p.Profile("server.port"))
+	assert.Equal(PROFILES_PATH+"override/app.properties", p.Source("server.port"))
+	assert.Equal("dev", p.Profile("db.url"))
+	assert.Equal(PROFILES_PATH+"config/app-dev.properties", p.Source("db.url"))
+	assert.Equal("local", p.Profile("log.level"))
+
+	// Overridden values are no longer from the profile
+	p.Set("db.url", "postgres://env-db/app", "environment variable DB_URL")
+	assert.Equal("", p.Profile("db.url"))
+}
+
+func TestProfilesFromEnv(t *testing.T) {
+	assert := assert.New(t)
+	cfg := ProfileConfig{BaseName: "app", SearchPath: []string{PROFILES_PATH + "config"}}
+
+	t.Setenv(PROFILES_ENV, "dev")
+	p, err := LoadProfiles(cfg)
+	assert.Nil(err)
+	assert.Equal("debug", p.MustString("log.level"))
+	assert.Equal("dev", p.Profile("log.level"))
+
+	t.Setenv(PROFILES_ENV, "")
+	p, err = LoadProfiles(cfg)
+	assert.Nil(err)
+	_, exists := p.Get("log.level")
+	assert.False(exists)
+
+	// Profiles argument takes precedence over the environment variable
+	t.Setenv(PROFILES_ENV, "missing")
+	cfg.Profiles = []string{"local"}
+	p, err = LoadProfiles(cfg)
+	assert.Nil(err)
+	assert.Equal("local", p.Profile("log.level"))
+}
+
+func TestLoadProfilesErrors(t *testing.T) {
+	assert := assert.New(t)
+	_, err := LoadProfiles(ProfileConfig{})
+	assert.ErrorContains(err, "base name of properties files is required")
+
+	_, err = LoadProfiles(ProfileConfig{BaseName: "app", Profiles: []string{"dev"}})
+	assert.ErrorContains(err, "properties file app.properties is not found in search path [.]")
+
+	searchPath := []string{PROFILES_PATH + "config", PROFILES_PATH + "override"}
+	_, err = LoadProfiles(ProfileConfig{BaseName: "app", SearchPath: searchPath, Profiles: []string{"prod"}})
+	assert.ErrorContains(err, "properties file app-prod.properties is not found in search path ["+PROFILES_PATH+"config "+PROFILES_PATH+"override]")
+
+	_, err = LoadProfiles(ProfileConfig{BaseName: "app", SearchPath: searchPath, Profiles: []string{"broken"}})
+	assert.ErrorContains(err, "invalid property broken, in file "+PROFILES_PATH+"override/app-broken.properties")
+
+	p, err := LoadProfiles(ProfileConfig{BaseName: "app", SearchPath: []string{PROFILES_PATH + "config"}, Extension: ".ini", Profiles: []string{"dev"}})
+	assert.Nil(p)
+	assert.ErrorContains(err, "properties file app.ini is not found")
+}
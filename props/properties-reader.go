@@ -9,27 +9,46 @@ import (
 )
 
 func ReadFiles(filePaths ...string) (map[string]string, error) {
-	props := map[string]string{}
+	p, err := ReadProperties(filePaths...)
+	if err != nil {
+		return nil, err
+	}
+	return p.ToMap(), nil
+}
+
+// Reads properties files, with values of later files overriding earlier ones. Source of each key is its file path
+func ReadProperties(filePaths ...string) (*Properties, error) {
+	p := New()
 	for _, filePath := range filePaths {
-		pBytes, fileReadErr := os.ReadFile(filePath)
-		if fileReadErr != nil {
-			return nil, logger.WrapAndLogError(fileReadErr, "error in reading file "+filePath)
+		if err := p.readFile(filePath, ""); err != nil {
+			return nil, err
 		}
-		propFile := string(pBytes)
-		propLines := strings.Split(propFile, "\n")
+	}
+	return p, nil
+}
+
+func (p *Properties) readFile(filePath string, profile string) error {
+	pBytes, fileReadErr := os.ReadFile(filePath)
+	if fileReadErr != nil {
+		return logger.WrapAndLogError(fileReadErr, "error in reading file "+filePath)
+	}
+	propFile := string(pBytes)
+	propLines := strings.Split(propFile, "\n")
 
-		for _, prop := range propLines {
-			prop = strings.TrimSpace(prop)
-			if !strings.HasPrefix(prop, "#") && prop != "" {
-				propParts := strings.SplitN(prop, "=", 2)
-				if len(propParts) != 2 {
-					return nil, fmt.Errorf("invalid property %s, in file %s", prop, filePath)
-				}
-				key := strings.TrimSpace(propParts[0])
-				value := strings.Trim(strings.TrimSpace(propParts[1]), "\"")
-				props[key] = value
+	for _, prop := range propLines {
+		prop = strings.TrimSpace(prop)
+		if !strings.HasPrefix(prop, "#") && prop != "" {
+			propParts := strings.SplitN(prop, "=", 2)
+			if len(propParts) != 2 {
+				return fmt.Errorf("invalid property %s, in file %s", prop, filePath)
+			}
+			key := strings.TrimSpace(propParts[0])
+			value := strings.Trim(strings.TrimSpace(propParts[1]), "\"")
+			p.Set(key, value, filePath)
+			if profile != "" {
+				p.profiles[key] = profile
 			}
 		}
 	}
-	return props, nil
+	return nil
 }
@@ -0,0 +1,134 @@
+package props
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+)
+
+var ErrUnresolvedPlaceholder = errors.New("unresolved placeholder")
+var ErrPlaceholderCycle = errors.New("placeholder cycle")
+
+type resolver struct {
+	p        *Properties
+	lookup   EnvLookup
+	resolved map[string]string
+	failed   map[string]bool
+	visiting []string
+	errs     []error
+}
+
+// Replaces placeholders in values. ${name} is replaced by the value of property name, or else environment variable name.
+// ${name:default} uses default, if neither exists. Values of referenced properties and defaults are resolved recursively.
+// Fails, without modifying values, if a placeholder is unresolved, unclosed, or properties reference each other in a cycle
+func (p *Properties) Resolve() error {
+	return p.ResolveLookup(os.LookupEnv)
+}
+
+// Same as Resolve, with environment variables from lookup
+func (p *Properties) ResolveLookup(lookup EnvLookup) error {
+	r := &resolver{
+		p:        p,
+		lookup:   lookup,
+		resolved: map[string]string{},
+		failed:   map[string]bool{},
+	}
+	for _, key := range p.Keys() {
+		r.resolveKey(key)
+	}
+	if len(r.errs) > 0 {
+		return fmt.Errorf("error while resolving placeholders\n%w", errors.Join(r.errs...))
+	}
+	for key, value := range r.resolved {
+		p.values[key] = value
+	}
+	return nil
+}
+
+// Returns false, if the value of key, or of a property it references, could not be resolved
+func (r *resolver) resolveKey(key string) (string, bool) {
+	if value, exists := r.resolved[key]; exists {
+		return value, true
+	}
+	if r.failed[key] {
+		return "", false
+	}
+	if i := slices.Index(r.visiting, key); i >= 0 {
+		cycle := append(slices.Clone(r.visiting[i:]), key)
+		r.fail(key, fmt.Errorf("%w %v, in %v", ErrPlaceholderCycle, strings.Join(cycle, " -> "), r.p.sources[key]))
+		return "", false
+	}
+	r.visiting = append(r.visiting, key)
+	value, ok := r.interpolate(key, r.p.values[key])
+	r.visiting = r.visiting[:len(r.visiting)-1]
+	if !ok {
+		r.failed[key] = true
+		return "", false
+	}
+	r.resolved[key] = value
+	return value, true
+}
+
+func (r *resolver) fail(key string, err error) {
+	r.failed[key] = true
+	r.errs = append(r.errs, err)
+}
+
+func (r *resolver) interpolate(key string, value string) (string, bool) {
+	sb := strings.Builder{}
+	rest := value
+	for {
+		start := strings.Index(rest, "${")
+		if start < 0 {
+			sb.WriteString(rest)
+			return sb.String(), true
+		}
+		sb.WriteString(rest[:start])
+		end := closingBrace(rest, start+2)
+		if end < 0 {
+			r.fail(key, fmt.Errorf("unclosed placeholder '%v' in property %v, in %v", rest[start:], key, r.p.sources[key]))
+			return "", false
+		}
+		replacement, ok := r.resolvePlaceholder(key, rest[start+2:end])
+		if !ok {
+			return "", false
+		}
+		sb.WriteString(replacement)
+		rest = rest[end+1:]
+	}
+}
+
+func (r *resolver) resolvePlaceholder(key string, placeholder string) (string, bool) {
+	name, def, hasDefault := strings.Cut(placeholder, ":")
+	name = strings.TrimSpace(name)
+	if _, exists := r.p.values[name]; exists {
+		return r.resolveKey(name)
+	}
+	if value, exists := r.lookup(name); exists {
+		return value, true
+	}
+	if hasDefault {
+		return r.interpolate(key, def)
+	}
+	r.fail(key, fmt.Errorf("%w ${%v} in property %v, in %v", ErrUnresolvedPlaceholder, placeholder, key, r.p.sources[key]))
+	return "", false
+}
+
+// Returns index of the brace closing the placeholder, which starts before from, counting nested placeholders
+func closingBrace(s string, from int) int {
+	depth := 0
+	for i := from; i < len(s); i++ {
+		switch {
+		case strings.HasPrefix(s[i:], "${"):
+			depth++
+			i++
+		case s[i] == '}' && depth == 0:
+			return i
+		case s[i] == '}':
+			depth--
+		}
+	}
+	return -1
+}
@@ -0,0 +1,78 @@
+package props
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func propsOf(values map[string]string) *Properties {
+	p := New()
+	for key, value := range values {
+		p.Set(key, value, "app.properties")
+	}
+	return p
+}
+
+func TestResolve(t *testing.T) {
+	assert := assert.New(t)
+	p := propsOf(map[string]string{
+		"app.home":   "${HOME_DIR}/app",
+		"log.dir":    "${app.home}/logs",
+		"log.file":   "${log.dir}/${app.name:service}.log",
+		"db.url":     "postgres://${DB_HOST:localhost}:${DB_PORT:${db.port}}/app",
+		"db.port":    "5432",
+		"greeting":   "costs $5, {not} a placeholder",
+		"nested.def": "${MISSING:${MISSING_TOO:${app.home}}}",
+	})
+	err := p.ResolveLookup(envOf(map[string]string{"HOME_DIR": "/opt", "DB_HOST": "db"}))
+	assert.Nil(err)
+	assert.Equal("/opt/app", p.MustString("app.home"))
+	assert.Equal("/opt/app/logs", p.MustString("log.dir"))
+	assert.Equal("/opt/app/logs/service.log", p.MustString("log.file"))
+	assert.Equal("postgres://db:5432/app", p.MustString("db.url"))
+	assert.Equal("costs $5, {not} a placeholder", p.MustString("greeting"))
+	assert.Equal("/opt/app", p.MustString("nested.def"))
+	assert.Equal("app.properties", p.Source("log.dir"))
+
+	// Property takes precedence over environment variable, and empty default is allowed
+	p = propsOf(map[string]string{"name": "prop", "a": "${name}", "b": "${UNSET:}"})
+	assert.Nil(p.ResolveLookup(envOf(map[string]string{"name": "env"})))
+	assert.Equal("prop", p.MustString("a"))
+	assert.Equal("", p.MustString("b"))
+
+	t.Setenv("PROPS_TEST_HOME", "/home/test")
+	p = propsOf(map[string]string{"dir": "${PROPS_TEST_HOME}/app"})
+	assert.Nil(p.Resolve())
+	assert.Equal("/home/test/app", p.MustString("dir"))
+}
+
+func TestResolveErrors(t *testing.T) {
+	assert := assert.New(t)
+	noEnv := envOf(nil)
+	p := propsOf(map[string]string{
+		"log.dir":  "${app.home}/logs",
+		"log.file": "${log.dir}/app.log",
+		"ok":       "value",
+	})
+	err := p.ResolveLookup(noEnv)
+	assert.ErrorIs(err, ErrUnresolvedPlaceholder)
+	assert.EqualError(err, "error while resolving placeholders\nunresolved placeholder ${app.home} in property log.dir, in app.properties")
+	assert.Equal("${app.home}/logs", p.MustString("log.dir"))
+
+	p = propsOf(map[string]string{
+		"a":    "${b}",
+		"b":    "x${c}",
+		"c":    "${a}",
+		"self": "${self:default}",
+	})
+	err = p.ResolveLookup(noEnv)
+	assert.ErrorIs(err, ErrPlaceholderCycle)
+	assert.ErrorContains(err, "placeholder cycle a -> b -> c -> a, in app.properties")
+	assert.ErrorContains(err, "placeholder cycle self -> self, in app.properties")
+
+	p = propsOf(map[string]string{"a": "${b", "c": "${MISSING:${ALSO_MISSING}}"})
+	err = p.ResolveLookup(noEnv)
+	assert.ErrorContains(err, "unclosed placeholder '${b' in property a, in app.properties")
+	assert.ErrorContains(err, "unresolved placeholder ${ALSO_MISSING} in property c, in app.properties")
+}
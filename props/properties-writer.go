@@ -0,0 +1,67 @@
+package props
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/wizards-0/go-pins/logger"
+)
+
+// Sets values of keys in a properties file. Existing keys are updated in place, keeping comments and line order,
+// and new keys are appended in sorted order. Quotes around existing values are kept
+func UpdateFile(filePath string, values map[string]string) error {
+	info, statErr := os.Stat(filePath)
+	if statErr != nil {
+		return logger.WrapAndLogError(statErr, "error in reading file "+filePath)
+	}
+	pBytes, fileReadErr := os.ReadFile(filePath)
+	if fileReadErr != nil {
+		return logger.WrapAndLogError(fileReadErr, "error in reading file "+filePath)
+	}
+	lines := strings.Split(string(pBytes), "\n")
+	updated := map[string]bool{}
+	for i, line := range lines {
+		prop := strings.TrimSpace(line)
+		if strings.HasPrefix(prop, "#") || prop == "" {
+			continue
+		}
+		keyPart, valuePart, found := strings.Cut(line, "=")
+		if !found {
+			return fmt.Errorf("invalid property %s, in file %s", prop, filePath)
+		}
+		key := strings.TrimSpace(keyPart)
+		if value, exists := values[key]; exists {
+			oldValue := strings.TrimSpace(valuePart)
+			if len(oldValue) > 1 && strings.HasPrefix(oldValue, "\"") && strings.HasSuffix(oldValue, "\"") {
+				value = "\"" + value + "\""
+			}
+			spacing := valuePart[:len(valuePart)-len(strings.TrimLeft(valuePart, " \t"))]
+			lines[i] = keyPart + "=" + spacing + value
+			updated[key] = true
+		}
+	}
+
+	newKeys := []string{}
+	for key := range values {
+		if !updated[key] {
+			newKeys = append(newKeys, key)
+		}
+	}
+	slices.Sort(newKeys)
+	if len(newKeys) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	for _, key := range newKeys {
+		lines = append(lines, key+"="+values[key])
+	}
+	if len(newKeys) > 0 {
+		lines = append(lines, "")
+	}
+
+	if err := os.WriteFile(filePath, []byte(strings.Join(lines, "\n")), info.Mode().Perm()); err != nil {
+		return logger.WrapAndLogError(err, "error in writing file "+filePath)
+	}
+	return nil
+}
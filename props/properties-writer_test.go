@@ -0,0 +1,46 @@
+package props
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUpdateFile(t *testing.T) {
+	assert := assert.New(t)
+	filePath := filepath.Join(t.TempDir(), "app.properties")
+	os.WriteFile(filePath, []byte("# app settings\nmain.version=v0.1.33\nPORT = \"8080\"\n\nNAME=app\n"), 0640)
+
+	err := UpdateFile(filePath, map[string]string{"main.version": "v0.2.0", "PORT": "9090", "B_NEW": "b", "A_NEW": "a"})
+	assert.Nil(err)
+	content, _ := os.ReadFile(filePath)
+	assert.Equal("# app settings\nmain.version=v0.2.0\nPORT = \"9090\"\n\nNAME=app\nA_NEW=a\nB_NEW=b\n", string(content))
+
+	props, err := ReadFiles(filePath)
+	assert.Nil(err)
+	assert.Equal("9090", props["PORT"])
+	assert.Equal("v0.2.0", props["main.version"])
+	info, _ := os.Stat(filePath)
+	assert.Equal(os.FileMode(0640), info.Mode().Perm())
+
+	// File without trailing new line
+	os.WriteFile(filePath, []byte("main.version=v0.1.33"), 0640)
+	assert.Nil(UpdateFile(filePath, map[string]string{"main.version": "v0.1.34"}))
+	content, _ = os.ReadFile(filePath)
+	assert.Equal("main.version=v0.1.34", string(content))
+}
+
+func TestUpdateFileErrors(t *testing.T) {
+	assert := assert.New(t)
+	err := UpdateFile("../invalid-path", map[string]string{"a": "b"})
+	assert.ErrorContains(err, "error in reading file")
+
+	err = UpdateFile("../resources/test/properties/invalid.properties", map[string]string{"a": "b"})
+	assert.ErrorContains(err, "invalid property")
+
+	dir := t.TempDir()
+	err = UpdateFile(dir, map[string]string{"a": "b"})
+	assert.ErrorContains(err, "error in reading file")
+}
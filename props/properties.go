@@ -0,0 +1,219 @@
+package props
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/wizards-0/go-pins/logger"
+)
+
+var ErrMissingProperty = errors.New("missing property")
+var ErrInvalidProperty = errors.New("invalid property")
+
+// Property values with the source they are read from, e.g. the file path.
+// Typed getters come in 3 variants:
+//   - Int(key, def) returns def if key is missing, or if its value is invalid, after logging the error
+//   - GetInt(key) returns ErrMissingProperty or ErrInvalidProperty, naming the key and source of the value
+//   - MustInt(key) panics on the errors of GetInt
+type Properties struct {
+	values   map[string]string
+	sources  map[string]string
+	profiles map[string]string
+}
+
+func New() *Properties {
+	return &Properties{
+		values:   map[string]string{},
+		sources:  map[string]string{},
+		profiles: map[string]string{},
+	}
+}
+
+// Sets value of key, read from source, e.g. the file path
+func (p *Properties) Set(key string, value string, source string) {
+	p.values[key] = value
+	p.sources[key] = source
+	delete(p.profiles, key)
+}
+
+func (p *Properties) Get(key string) (string, bool) {
+	value, exists := p.values[key]
+	return value, exists
+}
+
+// Returns the source of key's value, or empty string if key is missing
+func (p *Properties) Source(key string) string {
+	return p.sources[key]
+}
+
+// Returns the profile, whose file supplied key's value. Empty for values from base files or other sources
+func (p *Properties) Profile(key string) string {
+	return p.profiles[key]
+}
+
+// Returns all keys in sorted order
+func (p *Properties) Keys() []string {
+	return slices.Sorted(maps.Keys(p.values))
+}
+
+func (p *Properties) ToMap() map[string]string {
+	return maps.Clone(p.values)
+}
+
+func (p *Properties) String(key string, def string) string {
+	return getOrDefault(p, key, def, p.GetString)
+}
+
+func (p *Properties) GetString(key string) (string, error) {
+	return get(p, key, "string", parseString)
+}
+
+func (p *Properties) MustString(key string) string {
+	return must(p.GetString(key))
+}
+
+func (p *Properties) Int(key string, def int) int {
+	return getOrDefault(p, key, def, p.GetInt)
+}
+
+func (p *Properties) GetInt(key string) (int, error) {
+	return get(p, key, "int", strconv.Atoi)
+}
+
+func (p *Properties) MustInt(key string) int {
+	return must(p.GetInt(key))
+}
+
+// Accepts 1, t, T, TRUE, true, True, 0, f, F, FALSE, false, False
+func (p *Properties) Bool(key string, def bool) bool {
+	return getOrDefault(p, key, def, p.GetBool)
+}
+
+func (p *Properties) GetBool(key string) (bool, error) {
+	return get(p, key, "bool", strconv.ParseBool)
+}
+
+func (p *Properties) MustBool(key string) bool {
+	return must(p.GetBool(key))
+}
+
+// Accepts time.ParseDuration format, e.g. 300ms, 1m30s
+func (p *Properties) Duration(key string, def time.Duration) time.Duration {
+	return getOrDefault(p, key, def, p.GetDuration)
+}
+
+func (p *Properties) GetDuration(key string) (time.Duration, error) {
+	return get(p, key, "duration", time.ParseDuration)
+}
+
+func (p *Properties) MustDuration(key string) time.Duration {
+	return must(p.GetDuration(key))
+}
+
+func (p *Properties) Float(key string, def float64) float64 {
+	return getOrDefault(p, key, def, p.GetFloat)
+}
+
+func (p *Properties) GetFloat(key string) (float64, error) {
+	return get(p, key, "float", parseFloat)
+}
+
+func (p *Properties) MustFloat(key string) float64 {
+	return must(p.GetFloat(key))
+}
+
+// Comma separated values, e.g. 'a, b,c' is [a b c]. Values are trimmed and empty values are skipped
+func (p *Properties) StringSlice(key string, def []string) []string {
+	return getOrDefault(p, key, def, p.GetStringSlice)
+}
+
+func (p *Properties) GetStringSlice(key string) ([]string, error) {
+	return get(p, key, "string slice", parseStringSlice)
+}
+
+func (p *Properties) MustStringSlice(key string) []string {
+	return must(p.GetStringSlice(key))
+}
+
+// Comma separated key=value entries, e.g. 'sslmode=disable, timeout=5s'. Keys and values are trimmed
+func (p *Properties) Map(key string, def map[string]string) map[string]string {
+	return getOrDefault(p, key, def, p.GetMap)
+}
+
+func (p *Properties) GetMap(key string) (map[string]string, error) {
+	return get(p, key, "map", parseMap)
+}
+
+func (p *Properties) MustMap(key string) map[string]string {
+	return must(p.GetMap(key))
+}
+
+func get[T any](p *Properties, key string, typeName string, parse func(string) (T, error)) (T, error) {
+	var zero T
+	value, exists := p.values[key]
+	if !exists {
+		return zero, fmt.Errorf("%w %v", ErrMissingProperty, key)
+	}
+	parsed, err := parse(value)
+	if err != nil {
+		return zero, fmt.Errorf("%w %v='%v' in %v, expected %v\n%w", ErrInvalidProperty, key, value, p.sources[key], typeName, err)
+	}
+	return parsed, nil
+}
+
+func getOrDefault[T any](p *Properties, key string, def T, getter func(string) (T, error)) T {
+	if _, exists := p.values[key]; !exists {
+		return def
+	}
+	value, err := getter(key)
+	if err != nil {
+		logger.Error(err)
+		return def
+	}
+	return value
+}
+
+func must[T any](value T, err error) T {
+	if err != nil {
+		panic(err)
+	}
+	return value
+}
+
+func parseString(value string) (string, error) {
+	return value, nil
+}
+
+func parseFloat(value string) (float64, error) {
+	return strconv.ParseFloat(value, 64)
+}
+
+func parseStringSlice(value string) ([]string, error) {
+	values := []string{}
+	for _, v := range strings.Split(value, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+	return values, nil
+}
+
+func parseMap(value string) (map[string]string, error) {
+	entries := map[string]string{}
+	for _, entry := range strings.Split(value, ",") {
+		if strings.TrimSpace(entry) == "" {
+			continue
+		}
+		k, v, found := strings.Cut(entry, "=")
+		if !found || strings.TrimSpace(k) == "" {
+			return nil, fmt.Errorf("entry '%v' is not of format key=value", strings.TrimSpace(entry))
+		}
+		entries[strings.TrimSpace(k)] = strings.TrimSpace(v)
+	}
+	return entries, nil
+}
@@ -0,0 +1,111 @@
+package props
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wizards-0/go-pins/logger"
+)
+
+const TYPED_PATH = "../resources/test/properties/typed.properties"
+
+func TestReadProperties(t *testing.T) {
+	assert := assert.New(t)
+	p, err := ReadProperties("../resources/test/properties/common.properties", "../resources/test/properties/local.properties")
+	assert.Nil(err)
+	value, exists := p.Get("PORT")
+	assert.True(exists)
+	assert.Equal("4002", value)
+	assert.Equal("../resources/test/properties/local.properties", p.Source("PORT"))
+	assert.Equal([]string{"ENV_NAME", "PORT", "PWD"}, p.Keys())
+	assert.Equal(map[string]string{"ENV_NAME": "local", "PORT": "4002", "PWD": "P22=\\"}, p.ToMap())
+
+	_, exists = p.Get("missing")
+	assert.False(exists)
+	assert.Equal("", p.Source("missing"))
+
+	p.Set("PORT", "9090", "flags")
+	assert.Equal("9090", p.MustString("PORT"))
+	assert.Equal("flags", p.Source("PORT"))
+
+	_, err = ReadProperties("../invalid-path")
+	assert.ErrorContains(err, "error in reading file")
+}
+
+func TestTypedGetters(t *testing.T) {
+	assert := assert.New(t)
+	p, err := ReadProperties(TYPED_PATH)
+	assert.Nil(err)
+
+	assert.Equal("8080", p.MustString("server.port"))
+	assert.Equal(8080, p.MustInt("server.port"))
+	assert.True(p.MustBool("server.debug"))
+	assert.Equal(90*time.Second, p.MustDuration("server.timeout"))
+	assert.Equal(0.75, p.MustFloat("server.ratio"))
+	assert.Equal(8080.0, p.MustFloat("server.port"))
+	assert.Equal([]string{"a.example.com", "b.example.com"}, p.MustStringSlice("server.hosts"))
+	assert.Equal(map[string]string{"sslmode": "disable", "connect_timeout": "5"}, p.MustMap("db.params"))
+
+	p.Set("empty", "", "test")
+	assert.Equal([]string{}, p.MustStringSlice("empty"))
+	assert.Equal(map[string]string{}, p.MustMap("empty"))
+	assert.Equal("", p.String("empty", "def"))
+}
+
+func TestDefaults(t *testing.T) {
+	assert := assert.New(t)
+	buf := bytes.Buffer{}
+	logger.SetWriter(&buf, &buf, &buf, &buf)
+	defer logger.ResetWriters()
+	p, _ := ReadProperties(TYPED_PATH)
+
+	assert.Equal("def", p.String("missing", "def"))
+	assert.Equal(1, p.Int("missing", 1))
+	assert.Equal(8080, p.Int("server.port", 1))
+	assert.False(p.Bool("missing", false))
+	assert.Equal(time.Second, p.Duration("missing", time.Second))
+	assert.Equal(1.5, p.Float("missing", 1.5))
+	assert.Equal([]string{"x"}, p.StringSlice("missing", []string{"x"}))
+	assert.Equal(map[string]string{"k": "v"}, p.Map("missing", map[string]string{"k": "v"}))
+
+	// Invalid values fall back to default, and are logged
+	assert.Equal(1, p.Int("bad.int", 1))
+	assert.Contains(buf.String(), "invalid property bad.int='80a' in "+TYPED_PATH+", expected int")
+	assert.False(p.Bool("server.port", false))
+	assert.Equal(time.Second, p.Duration("server.port", time.Second))
+	assert.Equal(1.5, p.Float("server.hosts", 1.5))
+	assert.Nil(p.Map("bad.map", nil))
+}
+
+func TestGetterErrors(t *testing.T) {
+	assert := assert.New(t)
+	p, _ := ReadProperties(TYPED_PATH)
+
+	_, err := p.GetString("missing")
+	assert.ErrorIs(err, ErrMissingProperty)
+	assert.EqualError(err, "missing property missing")
+
+	_, err = p.GetInt("bad.int")
+	assert.ErrorIs(err, ErrInvalidProperty)
+	assert.ErrorContains(err, "invalid property bad.int='80a' in "+TYPED_PATH+", expected int")
+	_, err = p.GetBool("server.port")
+	assert.ErrorContains(err, "invalid property server.port='8080' in "+TYPED_PATH+", expected bool")
+	_, err = p.GetDuration("server.port")
+	assert.ErrorContains(err, "expected duration")
+	_, err = p.GetFloat("server.hosts")
+	assert.ErrorContains(err, "expected float")
+	_, err = p.GetMap("bad.map")
+	assert.ErrorContains(err, "invalid property bad.map='sslmode' in "+TYPED_PATH+", expected map\nentry 'sslmode' is not of format key=value")
+	_, err = p.GetStringSlice("missing")
+	assert.ErrorIs(err, ErrMissingProperty)
+
+	assert.PanicsWithError("missing property missing", func() { p.MustString("missing") })
+	assert.Panics(func() { p.MustInt("bad.int") })
+	assert.Panics(func() { p.MustBool("missing") })
+	assert.Panics(func() { p.MustDuration("missing") })
+	assert.Panics(func() { p.MustFloat("missing") })
+	assert.Panics(func() { p.MustStringSlice("missing") })
+	assert.Panics(func() { p.MustMap("bad.map") })
+}
@@ -1,16 +1,11 @@
 #!/bin/sh
 
-mainVersionLine=$(grep "main.version" version.ini)
-mainVersionKeyValue=(${mainVersionLine//=/ })
-mainVersion=${mainVersionKeyValue[1]}
-mainVersionParts=(${mainVersion//./ })
-mainVersionPatchOld=${mainVersionParts[2]}
-mainVersionPatchNew=$((mainVersionPatchOld+1))
-mainVersionNew="${mainVersionParts[0]}.${mainVersionParts[1]}.$mainVersionPatchNew"
-mainVersionLineNew="main.version=$mainVersionNew"
-sed -i "s/${mainVersionLine}/${mainVersionLineNew}/g" ./version.ini
+# Next version is computed from conventional commit messages since the last tag, defaulting to a patch release
+lastTag=$(git describe --tags --abbrev=0 2>/dev/null)
+range=${lastTag:+$lastTag..}HEAD
+mainVersionNew=$(git log --format=%B "$range" | go run ./cmd/bump -file version.ini -key main.version -commits - -fallback patch) || exit 1
 git commit -a -u -m "Automated commit for version increment"
 git push origin master
 git tag $mainVersionNew
 git push origin $mainVersionNew
-GOPROXY=proxy.golang.org go list -m github.com/wizards-0/go-pins@$mainVersionNew
\ No newline at end of file
+GOPROXY=proxy.golang.org go list -m github.com/wizards-0/go-pins@$mainVersionNew
@@ -0,0 +1 @@
+CREATE TABLE BASE(Id int);
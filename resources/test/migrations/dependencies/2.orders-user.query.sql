@@ -0,0 +1,2 @@
+-- migrator:depends 3
+ALTER TABLE ORDERS ADD COLUMN USER_ID int;
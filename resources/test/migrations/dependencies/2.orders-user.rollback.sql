@@ -0,0 +1 @@
+ALTER TABLE ORDERS DROP COLUMN USER_ID;
@@ -0,0 +1 @@
+CREATE TABLE ORDERS(Id int);
@@ -0,0 +1 @@
+CREATE TABLE USER_MASTER (ID INTEGER PRIMARY KEY);
\ No newline at end of file
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS USER_MASTER
\ No newline at end of file
@@ -0,0 +1,146 @@
+package semver
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+type BumpLevel int
+
+const (
+	BUMP_NONE BumpLevel = iota
+	BUMP_PRERELEASE
+	BUMP_PATCH
+	BUMP_MINOR
+	BUMP_MAJOR
+)
+
+var bumpLevelNames = map[BumpLevel]string{
+	BUMP_NONE:       "none",
+	BUMP_PRERELEASE: "prerelease",
+	BUMP_PATCH:      "patch",
+	BUMP_MINOR:      "minor",
+	BUMP_MAJOR:      "major",
+}
+
+func (l BumpLevel) String() string {
+	return bumpLevelNames[l]
+}
+
+func ParseBumpLevel(s string) (BumpLevel, error) {
+	for level, name := range bumpLevelNames {
+		if name == s {
+			return level, nil
+		}
+	}
+	return BUMP_NONE, fmt.Errorf("invalid bump level '%v'. Valid levels are none | prerelease | patch | minor | major", s)
+}
+
+// Returns the next major version, e.g. 1.2.3 -> 2.0.0. Pre-release of a major version is released, e.g. 2.0.0-rc.1 -> 2.0.0
+func (v Version) BumpMajor() Version {
+	if v.IsPrerelease() && v.Minor == 0 && v.Patch == 0 {
+		return Version{Major: v.Major}
+	}
+	return Version{Major: v.Major + 1}
+}
+
+// Returns the next minor version, e.g. 1.2.3 -> 1.3.0. Pre-release of a minor version is released, e.g. 1.3.0-rc.1 -> 1.3.0
+func (v Version) BumpMinor() Version {
+	if v.IsPrerelease() && v.Patch == 0 {
+		return Version{Major: v.Major, Minor: v.Minor}
+	}
+	return Version{Major: v.Major, Minor: v.Minor + 1}
+}
+
+// Returns the next patch version, e.g. 1.2.3 -> 1.2.4. Pre-release is released, e.g. 1.2.4-rc.1 -> 1.2.4
+func (v Version) BumpPatch() Version {
+	if v.IsPrerelease() {
+		return v.withoutPrerelease()
+	}
+	return Version{Major: v.Major, Minor: v.Minor, Patch: v.Patch + 1}
+}
+
+// Returns the next pre-release with identifier id, e.g. 1.2.0-rc.1 -> 1.2.0-rc.2, and 1.2.0 -> 1.2.1-rc.1.
+// Empty id keeps the current identifier
+func (v Version) BumpPrerelease(id string) Version {
+	next := v.withoutPrerelease()
+	if !v.IsPrerelease() {
+		next.Patch++
+		next.Prerelease = prereleaseStart(id)
+		return next
+	}
+	if id != "" && v.Prerelease[0] != id {
+		next.Prerelease = prereleaseStart(id)
+		return next
+	}
+	next.Prerelease = append([]string{}, v.Prerelease...)
+	last := len(next.Prerelease) - 1
+	if n, err := strconv.ParseUint(next.Prerelease[last], 10, 64); err == nil {
+		next.Prerelease[last] = strconv.FormatUint(n+1, 10)
+	} else {
+		next.Prerelease = append(next.Prerelease, "1")
+	}
+	return next
+}
+
+func prereleaseStart(id string) []string {
+	if id == "" {
+		return []string{"1"}
+	}
+	return []string{id, "1"}
+}
+
+// Returns v bumped by level. For BUMP_PRERELEASE, id is the pre-release identifier
+func (v Version) Bump(level BumpLevel, id string) Version {
+	switch level {
+	case BUMP_MAJOR:
+		return v.BumpMajor()
+	case BUMP_MINOR:
+		return v.BumpMinor()
+	case BUMP_PATCH:
+		return v.BumpPatch()
+	case BUMP_PRERELEASE:
+		return v.BumpPrerelease(id)
+	default:
+		return v
+	}
+}
+
+// Header of a conventional commit, e.g. 'feat(api)!: remove v1 endpoints'
+var commitHeaderPattern = regexp.MustCompile(`^([a-zA-Z]+)(\([^)]*\))?(!)?: \S`)
+
+// Returns the bump level required by conventional commit messages.
+// Breaking changes ('type!:' header or 'BREAKING CHANGE:' footer) require major, 'feat' minor, 'fix' & 'perf' patch.
+// Each line is checked on its own, so messages can be full commit bodies or one header per line
+func BumpLevelFromCommits(messages ...string) BumpLevel {
+	level := BUMP_NONE
+	for _, message := range messages {
+		for _, line := range strings.Split(message, "\n") {
+			level = max(level, lineBumpLevel(strings.TrimSpace(line)))
+		}
+	}
+	return level
+}
+
+func lineBumpLevel(line string) BumpLevel {
+	if strings.HasPrefix(line, "BREAKING CHANGE:") || strings.HasPrefix(line, "BREAKING-CHANGE:") {
+		return BUMP_MAJOR
+	}
+	match := commitHeaderPattern.FindStringSubmatch(line)
+	if match == nil {
+		return BUMP_NONE
+	}
+	if match[3] == "!" {
+		return BUMP_MAJOR
+	}
+	switch strings.ToLower(match[1]) {
+	case "feat":
+		return BUMP_MINOR
+	case "fix", "perf":
+		return BUMP_PATCH
+	default:
+		return BUMP_NONE
+	}
+}
@@ -0,0 +1,70 @@
+package semver
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBump(t *testing.T) {
+	assert := assert.New(t)
+	cases := []struct {
+		version  string
+		level    BumpLevel
+		id       string
+		expected string
+	}{
+		{"1.2.3", BUMP_MAJOR, "", "2.0.0"},
+		{"1.2.3+build.1", BUMP_MAJOR, "", "2.0.0"},
+		{"2.0.0-rc.1", BUMP_MAJOR, "", "2.0.0"},
+		{"2.1.0-rc.1", BUMP_MAJOR, "", "3.0.0"},
+		{"1.2.3", BUMP_MINOR, "", "1.3.0"},
+		{"1.3.0-rc.1", BUMP_MINOR, "", "1.3.0"},
+		{"1.3.1-rc.1", BUMP_MINOR, "", "1.4.0"},
+		{"1.2.3", BUMP_PATCH, "", "1.2.4"},
+		{"1.2.4-rc.1", BUMP_PATCH, "", "1.2.4"},
+		{"1.2.0-rc.1", BUMP_PRERELEASE, "", "1.2.0-rc.2"},
+		{"1.2.0-rc.1", BUMP_PRERELEASE, "rc", "1.2.0-rc.2"},
+		{"1.2.0-rc.9", BUMP_PRERELEASE, "", "1.2.0-rc.10"},
+		{"1.2.0-beta.3", BUMP_PRERELEASE, "rc", "1.2.0-rc.1"},
+		{"1.2.0-rc", BUMP_PRERELEASE, "", "1.2.0-rc.1"},
+		{"1.2.0-4", BUMP_PRERELEASE, "", "1.2.0-5"},
+		{"1.2.0", BUMP_PRERELEASE, "rc", "1.2.1-rc.1"},
+		{"1.2.0", BUMP_PRERELEASE, "", "1.2.1-1"},
+		{"1.2.0", BUMP_NONE, "", "1.2.0"},
+	}
+	for _, c := range cases {
+		assert.Equal(c.expected, MustParse(c.version).Bump(c.level, c.id).String(), c.version+" "+c.level.String())
+	}
+	// Bumping does not modify the original version
+	v := MustParse("1.2.0-rc.1")
+	v.BumpPrerelease("")
+	assert.Equal("1.2.0-rc.1", v.String())
+}
+
+func TestParseBumpLevel(t *testing.T) {
+	assert := assert.New(t)
+	for _, level := range []BumpLevel{BUMP_NONE, BUMP_PRERELEASE, BUMP_PATCH, BUMP_MINOR, BUMP_MAJOR} {
+		parsed, err := ParseBumpLevel(level.String())
+		assert.Nil(err)
+		assert.Equal(level, parsed)
+	}
+	_, err := ParseBumpLevel("huge")
+	assert.ErrorContains(err, "invalid bump level 'huge'. Valid levels are none | prerelease | patch | minor | major")
+}
+
+func TestBumpLevelFromCommits(t *testing.T) {
+	assert := assert.New(t)
+	assert.Equal(BUMP_NONE, BumpLevelFromCommits())
+	assert.Equal(BUMP_NONE, BumpLevelFromCommits("docs: update readme", "chore(deps): bump gin", "Merge branch 'main'"))
+	assert.Equal(BUMP_PATCH, BumpLevelFromCommits("docs: update readme", "fix(parser): handle empty files"))
+	assert.Equal(BUMP_PATCH, BumpLevelFromCommits("perf: cache compiled patterns"))
+	assert.Equal(BUMP_MINOR, BumpLevelFromCommits("fix: a\nfeat: b\n"))
+	assert.Equal(BUMP_MINOR, BumpLevelFromCommits("Feat(api): add endpoint"))
+	assert.Equal(BUMP_MAJOR, BumpLevelFromCommits("refactor!: drop CompareSemver"))
+	assert.Equal(BUMP_MAJOR, BumpLevelFromCommits("feat(api)!: remove v1"))
+	assert.Equal(BUMP_MAJOR, BumpLevelFromCommits("feat: new config\n\nBREAKING CHANGE: config keys renamed"))
+	assert.Equal(BUMP_MAJOR, BumpLevelFromCommits("fix: x\n\nBREAKING-CHANGE: y"))
+	// Not conventional commit headers
+	assert.Equal(BUMP_NONE, BumpLevelFromCommits("feat:missing space", "fixed the build", "feat(unclosed: x", "a BREAKING CHANGE: in text"))
+}
@@ -0,0 +1,336 @@
+package semver
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var ErrInvalidCalVer = errors.New("invalid calendar version")
+var ErrInvalidCalVerFormat = errors.New("invalid calendar version format")
+
+// Segments of calendar version formats, as per https://calver.org
+const (
+	// Full year, e.g. 2006
+	CAL_YYYY = "YYYY"
+	// Short year since 2000, e.g. 6, 16, 106
+	CAL_YY = "YY"
+	// Zero-padded short year, e.g. 06, 16, 106
+	CAL_0Y = "0Y"
+	// Month, e.g. 1, 12
+	CAL_MM = "MM"
+	// Zero-padded month, e.g. 01, 12
+	CAL_0M = "0M"
+	// Day, e.g. 1, 31
+	CAL_DD = "DD"
+	// Zero-padded day, e.g. 01, 31
+	CAL_0D = "0D"
+	// Increasing number for releases on the same date, starting at 0
+	CAL_MICRO = "MICRO"
+)
+
+// Longer segments first, so YYYY is not read as YY
+var calSegments = []string{CAL_YYYY, CAL_MICRO, CAL_YY, CAL_0Y, CAL_MM, CAL_0M, CAL_DD, CAL_0D}
+
+const calSeparators = ".-_"
+
+// Format of calendar versions, e.g. YYYY.MM.MICRO or YY.0M.DD.
+// Segments are separated by '.', '-' or '_'. Year is required, day requires month
+type CalVerFormat struct {
+	raw        string
+	segments   []string
+	separators []string
+}
+
+// Calendar version, e.g. 2026.10.2 in format YYYY.MM.MICRO. Parts not in the format are 0
+type CalVer struct {
+	Year   int
+	Month  int
+	Day    int
+	Micro  uint64
+	format CalVerFormat
+}
+
+func ParseCalVerFormat(format string) (CalVerFormat, error) {
+	f := CalVerFormat{raw: format}
+	rest := format
+	for rest != "" {
+		if len(f.segments) > len(f.separators) {
+			if !strings.ContainsRune(calSeparators, rune(rest[0])) {
+				return CalVerFormat{}, fmt.Errorf("%w '%v', expected one of '%v' after %v", ErrInvalidCalVerFormat, format, calSeparators, f.segments[len(f.segments)-1])
+			}
+			f.separators = append(f.separators, rest[:1])
+			rest = rest[1:]
+			continue
+		}
+		segment := ""
+		for _, candidate := range calSegments {
+			if strings.HasPrefix(rest, candidate) {
+				segment = candidate
+				break
+			}
+		}
+		if segment == "" {
+			return CalVerFormat{}, fmt.Errorf("%w '%v', unknown segment at '%v'. Valid segments are YYYY | YY | 0Y | MM | 0M | DD | 0D | MICRO", ErrInvalidCalVerFormat, format, rest)
+		}
+		f.segments = append(f.segments, segment)
+		rest = rest[len(segment):]
+	}
+	if err := f.validate(); err != nil {
+		return CalVerFormat{}, fmt.Errorf("%w '%v', %w", ErrInvalidCalVerFormat, format, err)
+	}
+	return f, nil
+}
+
+func MustParseCalVerFormat(format string) CalVerFormat {
+	f, err := ParseCalVerFormat(format)
+	if err != nil {
+		panic(err)
+	}
+	return f
+}
+
+func (f CalVerFormat) validate() error {
+	if len(f.segments) == 0 {
+		return errors.New("format is empty")
+	}
+	if len(f.separators) == len(f.segments) {
+		return errors.New("format ends with a separator")
+	}
+	seen := map[string]string{}
+	for _, segment := range f.segments {
+		part := calPart(segment)
+		if previous, exists := seen[part]; exists {
+			return fmt.Errorf("%v is repeated by %v and %v", part, previous, segment)
+		}
+		seen[part] = segment
+	}
+	if seen["year"] == "" {
+		return errors.New("year segment is required")
+	}
+	if seen["day"] != "" && seen["month"] == "" {
+		return errors.New("day segment requires month segment")
+	}
+	return nil
+}
+
+func calPart(segment string) string {
+	switch segment {
+	case CAL_YYYY, CAL_YY, CAL_0Y:
+		return "year"
+	case CAL_MM, CAL_0M:
+		return "month"
+	case CAL_DD, CAL_0D:
+		return "day"
+	default:
+		return "micro"
+	}
+}
+
+func (f CalVerFormat) String() string {
+	return f.raw
+}
+
+func (f CalVerFormat) has(part string) bool {
+	for _, segment := range f.segments {
+		if calPart(segment) == part {
+			return true
+		}
+	}
+	return false
+}
+
+// Parses a version of this format. Month and day are validated against the calendar, e.g. 2026.02.30 is invalid
+func (f CalVerFormat) Parse(s string) (CalVer, error) {
+	v, err := f.parse(s)
+	if err != nil {
+		return CalVer{}, fmt.Errorf("%w '%v' for format %v, %w", ErrInvalidCalVer, s, f.raw, err)
+	}
+	return v, nil
+}
+
+func (f CalVerFormat) MustParse(s string) CalVer {
+	v, err := f.Parse(s)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+func (f CalVerFormat) parse(s string) (CalVer, error) {
+	if len(f.segments) == 0 {
+		return CalVer{}, errors.New("format is empty")
+	}
+	v := CalVer{format: f}
+	rest := s
+	for i, segment := range f.segments {
+		if i > 0 {
+			if !strings.HasPrefix(rest, f.separators[i-1]) {
+				return CalVer{}, fmt.Errorf("expected '%v' before %v", f.separators[i-1], segment)
+			}
+			rest = rest[1:]
+		}
+		end := strings.IndexAny(rest, calSeparators)
+		if end < 0 {
+			end = len(rest)
+		}
+		if err := v.setSegment(segment, rest[:end]); err != nil {
+			return CalVer{}, err
+		}
+		rest = rest[end:]
+	}
+	if rest != "" {
+		return CalVer{}, fmt.Errorf("unexpected '%v' after %v", rest, f.segments[len(f.segments)-1])
+	}
+	if f.has("day") && v.Day > daysIn(v.Year, v.Month) {
+		return CalVer{}, fmt.Errorf("day %v is out of range for %v-%02d", v.Day, v.Year, v.Month)
+	}
+	return v, nil
+}
+
+func (v *CalVer) setSegment(segment string, value string) error {
+	if value == "" || !isNumeric(value) {
+		return fmt.Errorf("expected number for %v, found '%v'", segment, value)
+	}
+	padded := segment == CAL_0Y || segment == CAL_0M || segment == CAL_0D
+	if padded && len(value) < 2 {
+		return fmt.Errorf("%v has to be zero-padded to 2 digits, found '%v'", segment, value)
+	}
+	if !padded && len(value) > 1 && value[0] == '0' {
+		return fmt.Errorf("%v has leading zero in '%v'", segment, value)
+	}
+	if segment == CAL_MICRO {
+		micro, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			return fmt.Errorf("%v '%v' is out of range", segment, value)
+		}
+		v.Micro = micro
+		return nil
+	}
+	if len(value) > 4 {
+		return fmt.Errorf("%v '%v' is out of range", segment, value)
+	}
+	n, _ := strconv.Atoi(value)
+	switch segment {
+	case CAL_YYYY:
+		if len(value) != 4 {
+			return fmt.Errorf("%v has to be 4 digits, found '%v'", segment, value)
+		}
+		v.Year = n
+	case CAL_YY, CAL_0Y:
+		if len(value) > 3 {
+			return fmt.Errorf("%v '%v' is out of range", segment, value)
+		}
+		v.Year = 2000 + n
+	case CAL_MM, CAL_0M:
+		if n < 1 || n > 12 || len(value) > 2 {
+			return fmt.Errorf("month '%v' is out of range 1-12", value)
+		}
+		v.Month = n
+	default:
+		if n < 1 || n > 31 || len(value) > 2 {
+			return fmt.Errorf("day '%v' is out of range 1-31", value)
+		}
+		v.Day = n
+	}
+	return nil
+}
+
+func daysIn(year int, month int) int {
+	return time.Date(year, time.Month(month)+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
+
+// Compares versions of this format, so the format can be used as Comparator.
+// Versions, which are not valid for the format, are ordered after valid ones, in natural order
+func (f CalVerFormat) Compare(v1 string, v2 string) int {
+	c1, err1 := f.Parse(v1)
+	c2, err2 := f.Parse(v2)
+	switch {
+	case err1 == nil && err2 == nil:
+		return c1.Compare(c2)
+	case err1 == nil:
+		return -1
+	case err2 == nil:
+		return 1
+	default:
+		return CompareNatural(v1, v2)
+	}
+}
+
+// Returns the version of this format for date of now, e.g. 2026.10.0 for YYYY.MM.MICRO.
+// If current is already released for the same date, its MICRO is incremented, e.g. 2026.10.2 -> 2026.10.3.
+// Empty current returns the version for date of now.
+// Fails if current is invalid, is newer than date of now, or is of the same date and the format has no MICRO
+func (f CalVerFormat) Next(current string, now time.Time) (CalVer, error) {
+	next := CalVer{Year: now.Year(), format: f}
+	if f.has("month") {
+		next.Month = int(now.Month())
+	}
+	if f.has("day") {
+		next.Day = now.Day()
+	}
+	if current == "" {
+		return next, nil
+	}
+	v, err := f.Parse(current)
+	if err != nil {
+		return CalVer{}, err
+	}
+	micro := v.Micro
+	v.Micro = 0
+	switch v.Compare(next) {
+	case -1:
+		return next, nil
+	case 1:
+		return CalVer{}, fmt.Errorf("version %v is newer than the version for %v", current, now.Format(time.DateOnly))
+	}
+	if !f.has("micro") {
+		return CalVer{}, fmt.Errorf("version %v is already released for %v, and format %v has no MICRO", current, now.Format(time.DateOnly), f.raw)
+	}
+	next.Micro = micro + 1
+	return next, nil
+}
+
+func (v CalVer) String() string {
+	sb := strings.Builder{}
+	for i, segment := range v.format.segments {
+		if i > 0 {
+			sb.WriteString(v.format.separators[i-1])
+		}
+		switch segment {
+		case CAL_YYYY:
+			sb.WriteString(strconv.Itoa(v.Year))
+		case CAL_YY:
+			sb.WriteString(strconv.Itoa(v.Year - 2000))
+		case CAL_0Y:
+			sb.WriteString(fmt.Sprintf("%02d", v.Year-2000))
+		case CAL_MM:
+			sb.WriteString(strconv.Itoa(v.Month))
+		case CAL_0M:
+			sb.WriteString(fmt.Sprintf("%02d", v.Month))
+		case CAL_DD:
+			sb.WriteString(strconv.Itoa(v.Day))
+		case CAL_0D:
+			sb.WriteString(fmt.Sprintf("%02d", v.Day))
+		default:
+			sb.WriteString(strconv.FormatUint(v.Micro, 10))
+		}
+	}
+	return sb.String()
+}
+
+// Returns -1 if v < o, 0 if v == o and 1 if v > o, comparing year, month, day and micro
+func (v CalVer) Compare(o CalVer) int {
+	if c := compareUint(uint64(v.Year), uint64(o.Year)); c != 0 {
+		return c
+	}
+	if c := compareUint(uint64(v.Month), uint64(o.Month)); c != 0 {
+		return c
+	}
+	if c := compareUint(uint64(v.Day), uint64(o.Day)); c != 0 {
+		return c
+	}
+	return compareUint(v.Micro, o.Micro)
+}
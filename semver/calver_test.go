@@ -0,0 +1,151 @@
+package semver
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseCalVerFormat(t *testing.T) {
+	assert := assert.New(t)
+	for _, format := range []string{"YYYY.MM.MICRO", "YY.0M.DD", "YY.MM-MICRO", "0Y_0M_0D", "YYYY", "YYYY.MICRO"} {
+		f, err := ParseCalVerFormat(format)
+		assert.Nil(err, format)
+		assert.Equal(format, f.String())
+	}
+
+	cases := map[string]string{
+		"":               "format is empty",
+		"YYYY.":          "format ends with a separator",
+		"YYYY.MM.WW":     "unknown segment at 'WW'",
+		"YYYYMM":         "expected one of '.-_' after YYYY",
+		"YYYY.YY":        "year is repeated by YYYY and YY",
+		"MM.MICRO":       "year segment is required",
+		"YYYY.DD":        "day segment requires month segment",
+		"YYYY..MM":       "unknown segment at '.MM'",
+		"YYYY.MM.MICRO.": "format ends with a separator",
+	}
+	for format, msg := range cases {
+		_, err := ParseCalVerFormat(format)
+		assert.ErrorIs(err, ErrInvalidCalVerFormat, format)
+		assert.ErrorContains(err, msg, format)
+	}
+	assert.Panics(func() { MustParseCalVerFormat("XX") })
+}
+
+func TestParseCalVer(t *testing.T) {
+	assert := assert.New(t)
+	f := MustParseCalVerFormat("YYYY.MM.MICRO")
+	v, err := f.Parse("2026.10.2")
+	assert.Nil(err)
+	assert.Equal(2026, v.Year)
+	assert.Equal(10, v.Month)
+	assert.Equal(0, v.Day)
+	assert.Equal(uint64(2), v.Micro)
+	assert.Equal("2026.10.2", v.String())
+
+	v = MustParseCalVerFormat("YY.MM-MICRO").MustParse("26.10-1")
+	assert.Equal(2026, v.Year)
+	assert.Equal(uint64(1), v.Micro)
+	assert.Equal("26.10-1", v.String())
+
+	v = MustParseCalVerFormat("0Y.0M.0D").MustParse("06.02.09")
+	assert.Equal(2006, v.Year)
+	assert.Equal("06.02.09", v.String())
+
+	assert.Equal("6.2.9", MustParseCalVerFormat("YY.MM.DD").MustParse("6.2.9").String())
+	assert.Equal("24.2.29", MustParseCalVerFormat("YY.MM.DD").MustParse("24.2.29").String())
+}
+
+func TestInvalidCalVer(t *testing.T) {
+	assert := assert.New(t)
+	cases := map[string]map[string]string{
+		"YYYY.MM.MICRO": {
+			"2026.10":                      "expected '.' before MICRO",
+			"2026.10.1.1":                  "unexpected '.1' after MICRO",
+			"2026-10-1":                    "expected '.' before MM",
+			"26.10.1":                      "YYYY has to be 4 digits, found '26'",
+			"2026.13.0":                    "month '13' is out of range 1-12",
+			"2026.0.0":                     "month '0' is out of range 1-12",
+			"2026.010.0":                   "MM has leading zero in '010'",
+			"2026.10.01":                   "MICRO has leading zero in '01'",
+			"2026.10.a":                    "expected number for MICRO, found 'a'",
+			"2026.10.":                     "expected number for MICRO, found ''",
+			"20260.10.1":                   "YYYY '20260' is out of range",
+			"v2026.10.1":                   "expected number for YYYY, found 'v2026'",
+			"2026.10.99999999999999999999": "MICRO '99999999999999999999' is out of range",
+		},
+		"YY.0M.0D": {
+			"26.2.01":    "0M has to be zero-padded to 2 digits, found '2'",
+			"26.02.30":   "day 30 is out of range for 2026-02",
+			"25.02.29":   "day 29 is out of range for 2025-02",
+			"26.04.31":   "day 31 is out of range for 2026-04",
+			"26.04.32":   "day '32' is out of range 1-31",
+			"026.04.1":   "YY has leading zero in '026'",
+			"1026.04.01": "YY '1026' is out of range",
+		},
+	}
+	for format, versions := range cases {
+		f := MustParseCalVerFormat(format)
+		for s, msg := range versions {
+			_, err := f.Parse(s)
+			assert.ErrorIs(err, ErrInvalidCalVer, s)
+			assert.ErrorContains(err, "invalid calendar version '"+s+"' for format "+format, s)
+			assert.ErrorContains(err, msg, s)
+		}
+	}
+	assert.Panics(func() { MustParseCalVerFormat("YYYY").MustParse("26") })
+	_, err := CalVerFormat{}.Parse("2026")
+	assert.ErrorContains(err, "format is empty")
+}
+
+func TestCompareCalVer(t *testing.T) {
+	assert := assert.New(t)
+	f := MustParseCalVerFormat("YYYY.MM.MICRO")
+	verArr := []string{"2026.10.10", "2025.12.0", "invalid", "2026.10.2", "2026.9.0", "2026.1.0"}
+	slices.SortFunc(verArr, f.Compare)
+	assert.Equal([]string{"2025.12.0", "2026.1.0", "2026.9.0", "2026.10.2", "2026.10.10", "invalid"}, verArr)
+
+	var cmp Comparator = f
+	assert.Equal(0, cmp.Compare("2026.10.2", "2026.10.2"))
+	assert.Equal(1, cmp.Compare("a", "2026.10.2"))
+	assert.Equal(-1, cmp.Compare("a", "b"))
+
+	d := MustParseCalVerFormat("YY.0M.DD")
+	assert.Equal(-1, d.MustParse("26.09.30").Compare(d.MustParse("26.10.1")))
+	assert.Equal(1, d.MustParse("27.01.1").Compare(d.MustParse("26.12.31")))
+}
+
+func TestNextCalVer(t *testing.T) {
+	assert := assert.New(t)
+	now := time.Date(2026, time.October, 19, 10, 0, 0, 0, time.UTC)
+	f := MustParseCalVerFormat("YYYY.MM.MICRO")
+
+	cases := map[string]string{
+		"":           "2026.10.0",
+		"2026.9.4":   "2026.10.0",
+		"2025.12.7":  "2026.10.0",
+		"2026.10.0":  "2026.10.1",
+		"2026.10.12": "2026.10.13",
+	}
+	for current, expected := range cases {
+		next, err := f.Next(current, now)
+		assert.Nil(err, current)
+		assert.Equal(expected, next.String(), current)
+	}
+
+	next, err := MustParseCalVerFormat("YY.0M.0D-MICRO").Next("26.10.18-3", now)
+	assert.Nil(err)
+	assert.Equal("26.10.19-0", next.String())
+
+	_, err = f.Next("2026.11.0", now)
+	assert.ErrorContains(err, "version 2026.11.0 is newer than the version for 2026-10-19")
+
+	_, err = MustParseCalVerFormat("YY.0M.0D").Next("26.10.19", now)
+	assert.ErrorContains(err, "version 26.10.19 is already released for 2026-10-19, and format YY.0M.0D has no MICRO")
+
+	_, err = f.Next("2026.10", now)
+	assert.ErrorIs(err, ErrInvalidCalVer)
+}
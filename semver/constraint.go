@@ -0,0 +1,359 @@
+package semver
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var ErrInvalidConstraint = errors.New("invalid version constraint")
+
+// Requirement on versions, e.g. '>=1.2.0 <2.0.0', '^1.4', '~0.1.33', '1.x' or '1.2 - 1.4 || >=2.1'.
+// Supported syntax:
+//   - comparators =, >, >=, <, <= with full or partial versions, e.g. '>=1.2'
+//   - x-ranges '1.x', '1.2.*', '1' and '*'
+//   - tilde ranges '~1.2.3' (>=1.2.3 <1.3.0), caret ranges '^1.2.3' (>=1.2.3 <2.0.0, ^0.2.3 is >=0.2.3 <0.3.0)
+//   - hyphen ranges '1.2.3 - 2.3' (>=1.2.3 <2.4.0)
+//   - space or comma separated comparators, which all have to match, and '||' separated alternatives
+//
+// A pre-release version only satisfies a range, if a comparator of the range has a pre-release of the same major.minor.patch,
+// e.g. 1.3.0-rc.1 satisfies '>=1.3.0-beta' but not '>=1.2.0'
+type Constraint struct {
+	raw    string
+	ranges [][]comparator
+}
+
+type comparator struct {
+	op string
+	v  Version
+}
+
+// Version with optional minor & patch, e.g. '1', '1.2' or '1.x'. n is the number of specified parts
+type partialVersion struct {
+	v Version
+	n int
+}
+
+func ParseConstraint(s string) (Constraint, error) {
+	c := Constraint{raw: s}
+	if strings.TrimSpace(s) == "" {
+		return Constraint{}, fmt.Errorf("%w '%v', constraint is empty. Use '*' to allow any version", ErrInvalidConstraint, s)
+	}
+	for _, group := range strings.Split(s, "||") {
+		comparators, err := parseRange(group)
+		if err != nil {
+			return Constraint{}, fmt.Errorf("%w '%v', %w", ErrInvalidConstraint, s, err)
+		}
+		c.ranges = append(c.ranges, comparators)
+	}
+	return c, nil
+}
+
+func MustParseConstraint(s string) Constraint {
+	c, err := ParseConstraint(s)
+	if err != nil {
+		panic(err)
+	}
+	return c
+}
+
+// Returns true if version satisfies constraint. Fails if either of them is malformed
+func Satisfies(version string, constraint string) (bool, error) {
+	v, err := Parse(version)
+	if err != nil {
+		return false, err
+	}
+	c, err := ParseConstraint(constraint)
+	if err != nil {
+		return false, err
+	}
+	return c.Check(v), nil
+}
+
+// Returns the highest of versions which satisfies constraint, or empty string if none of them does.
+// Fails if constraint or any of the versions is malformed
+func MaxSatisfying(versions []string, constraint string) (string, error) {
+	c, err := ParseConstraint(constraint)
+	if err != nil {
+		return "", err
+	}
+	max := ""
+	var maxVersion Version
+	for _, s := range versions {
+		v, err := Parse(s)
+		if err != nil {
+			return "", err
+		}
+		if c.Check(v) && (max == "" || v.Compare(maxVersion) > 0) {
+			max, maxVersion = s, v
+		}
+	}
+	return max, nil
+}
+
+func (c Constraint) String() string {
+	return c.raw
+}
+
+func (c Constraint) Check(v Version) bool {
+	for _, comparators := range c.ranges {
+		if rangeMatches(comparators, v) {
+			return true
+		}
+	}
+	return false
+}
+
+func rangeMatches(comparators []comparator, v Version) bool {
+	for _, cmp := range comparators {
+		if !cmp.matches(v) {
+			return false
+		}
+	}
+	if !v.IsPrerelease() {
+		return true
+	}
+	for _, cmp := range comparators {
+		if cmp.v.IsPrerelease() && cmp.v.Major == v.Major && cmp.v.Minor == v.Minor && cmp.v.Patch == v.Patch {
+			return true
+		}
+	}
+	return false
+}
+
+func (cmp comparator) matches(v Version) bool {
+	c := v.Compare(cmp.v)
+	switch cmp.op {
+	case ">":
+		return c > 0
+	case ">=":
+		return c >= 0
+	case "<":
+		return c < 0
+	case "<=":
+		return c <= 0
+	default:
+		return c == 0
+	}
+}
+
+var operators = []string{">=", "<=", ">", "<", "=", "^", "~"}
+
+func parseRange(group string) ([]comparator, error) {
+	tokens := strings.FieldsFunc(group, func(r rune) bool {
+		return r == ' ' || r == '\t' || r == ','
+	})
+	if len(tokens) == 0 {
+		return nil, errors.New("empty range around '||'")
+	}
+	// Hyphen range, e.g. '1.2 - 2'
+	if len(tokens) == 3 && tokens[1] == "-" {
+		return parseHyphenRange(tokens[0], tokens[2])
+	}
+	comparators := []comparator{}
+	for i := 0; i < len(tokens); i++ {
+		token := tokens[i]
+		// Operator separated from its version by space, e.g. '>= 1.2'
+		if isOperator(token) {
+			if i+1 >= len(tokens) || strings.ContainsAny(tokens[i+1][:1], "<>=^~") {
+				return nil, fmt.Errorf("missing version after '%v'", token)
+			}
+			i++
+			token += tokens[i]
+		}
+		if token == "-" {
+			return nil, errors.New("hyphen range has to be of format '<version> - <version>'")
+		}
+		expanded, err := parseComparator(token)
+		if err != nil {
+			return nil, err
+		}
+		comparators = append(comparators, expanded...)
+	}
+	return comparators, nil
+}
+
+func isOperator(token string) bool {
+	for _, op := range operators {
+		if token == op {
+			return true
+		}
+	}
+	return false
+}
+
+func parseComparator(token string) ([]comparator, error) {
+	op := ""
+	for _, candidate := range operators {
+		if strings.HasPrefix(token, candidate) {
+			op = candidate
+			break
+		}
+	}
+	p, err := parsePartial(strings.TrimPrefix(token, op))
+	if err != nil {
+		return nil, fmt.Errorf("invalid version in '%v', %w", token, err)
+	}
+	switch op {
+	case "", "=":
+		return xRange(p), nil
+	case ">":
+		return greaterThan(p), nil
+	case ">=":
+		if p.n == 0 {
+			return []comparator{}, nil
+		}
+		return []comparator{{">=", p.v}}, nil
+	case "<":
+		return lessThan(p), nil
+	case "<=":
+		return lessThanOrEqual(p), nil
+	case "~":
+		return tildeRange(p), nil
+	default:
+		return caretRange(p), nil
+	}
+}
+
+func parsePartial(s string) (partialVersion, error) {
+	s = strings.TrimPrefix(s, "v")
+	if s == "" {
+		return partialVersion{}, errors.New("missing version")
+	}
+	if v, err := parse(s); err == nil {
+		return partialVersion{v: v, n: 3}, nil
+	}
+	core, _, _ := strings.Cut(s, "+")
+	if strings.Contains(core, "-") {
+		// Let the full parser explain what is wrong
+		_, err := parse(s)
+		return partialVersion{}, err
+	}
+	parts := strings.Split(core, ".")
+	if len(parts) > 3 {
+		return partialVersion{}, errors.New("expected format major[.minor[.patch]]")
+	}
+	nums := [3]uint64{}
+	n := 0
+	for i, part := range parts {
+		if part == "x" || part == "X" || part == "*" {
+			for _, rest := range parts[i+1:] {
+				if rest != "x" && rest != "X" && rest != "*" {
+					return partialVersion{}, fmt.Errorf("number '%v' after wildcard", rest)
+				}
+			}
+			break
+		}
+		num, err := parseNumber(part)
+		if err != nil {
+			return partialVersion{}, err
+		}
+		nums[i] = num
+		n++
+	}
+	return partialVersion{v: Version{Major: nums[0], Minor: nums[1], Patch: nums[2]}, n: n}, nil
+}
+
+// Lowest pre-release of a version, used as exclusive upper bound, so pre-releases of the bound do not match
+func lowest(major uint64, minor uint64, patch uint64) Version {
+	return Version{Major: major, Minor: minor, Patch: patch, Prerelease: []string{"0"}}
+}
+
+// Exclusive upper bound of the versions matching a partial version
+func upperBound(p partialVersion) Version {
+	if p.n == 1 {
+		return lowest(p.v.Major+1, 0, 0)
+	}
+	return lowest(p.v.Major, p.v.Minor+1, 0)
+}
+
+func xRange(p partialVersion) []comparator {
+	switch p.n {
+	case 0:
+		return []comparator{}
+	case 3:
+		return []comparator{{"=", p.v}}
+	default:
+		return []comparator{{">=", p.v}, {"<", upperBound(p)}}
+	}
+}
+
+func greaterThan(p partialVersion) []comparator {
+	switch p.n {
+	case 0:
+		// Nothing is greater than any version
+		return []comparator{{"<", lowest(0, 0, 0)}}
+	case 3:
+		return []comparator{{">", p.v}}
+	default:
+		return []comparator{{">=", upperBound(p).withoutPrerelease()}}
+	}
+}
+
+func lessThan(p partialVersion) []comparator {
+	switch p.n {
+	case 0:
+		return []comparator{{"<", lowest(0, 0, 0)}}
+	case 3:
+		return []comparator{{"<", p.v}}
+	default:
+		return []comparator{{"<", lowest(p.v.Major, p.v.Minor, 0)}}
+	}
+}
+
+func lessThanOrEqual(p partialVersion) []comparator {
+	switch p.n {
+	case 0:
+		return []comparator{}
+	case 3:
+		return []comparator{{"<=", p.v}}
+	default:
+		return []comparator{{"<", upperBound(p)}}
+	}
+}
+
+func tildeRange(p partialVersion) []comparator {
+	switch p.n {
+	case 0:
+		return []comparator{}
+	case 1:
+		return xRange(p)
+	default:
+		return []comparator{{">=", p.v}, {"<", lowest(p.v.Major, p.v.Minor+1, 0)}}
+	}
+}
+
+// Allows changes, which do not modify the left-most non-zero part
+func caretRange(p partialVersion) []comparator {
+	v := p.v
+	switch {
+	case p.n == 0:
+		return []comparator{}
+	case p.n == 1 || v.Major > 0:
+		return []comparator{{">=", v}, {"<", lowest(v.Major+1, 0, 0)}}
+	case p.n == 2 || v.Minor > 0:
+		return []comparator{{">=", v}, {"<", lowest(0, v.Minor+1, 0)}}
+	default:
+		return []comparator{{">=", v}, {"<", lowest(0, 0, v.Patch+1)}}
+	}
+}
+
+func parseHyphenRange(from string, to string) ([]comparator, error) {
+	lower, err := parsePartial(from)
+	if err != nil {
+		return nil, fmt.Errorf("invalid version in '%v', %w", from, err)
+	}
+	upper, err := parsePartial(to)
+	if err != nil {
+		return nil, fmt.Errorf("invalid version in '%v', %w", to, err)
+	}
+	comparators := []comparator{}
+	if lower.n > 0 {
+		comparators = append(comparators, comparator{">=", lower.v})
+	}
+	return append(comparators, lessThanOrEqual(upper)...), nil
+}
+
+func (v Version) withoutPrerelease() Version {
+	return Version{Major: v.Major, Minor: v.Minor, Patch: v.Patch}
+}
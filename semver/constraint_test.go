@@ -0,0 +1,119 @@
+package semver
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSatisfies(t *testing.T) {
+	assert := assert.New(t)
+	cases := []struct {
+		constraint string
+		matching   []string
+		other      []string
+	}{
+		{">=1.2.0 <2.0.0", []string{"1.2.0", "1.9.9"}, []string{"1.1.9", "2.0.0", "2.0.0-alpha", "1.5.0-rc.1"}},
+		{">=1.2.0, <2.0.0", []string{"1.2.0"}, []string{"2.0.0"}},
+		{">= 1.2 < 2", []string{"1.2.0", "1.99.0"}, []string{"2.0.0", "1.1.0"}},
+		{"^1.4", []string{"1.4.0", "1.99.0"}, []string{"1.3.9", "2.0.0"}},
+		{"^1.2.3", []string{"1.2.3", "1.9.0"}, []string{"1.2.2", "2.0.0-0"}},
+		{"^0.2.3", []string{"0.2.3", "0.2.9"}, []string{"0.3.0", "0.2.2"}},
+		{"^0.0.3", []string{"0.0.3"}, []string{"0.0.4", "0.0.2"}},
+		{"^0.0", []string{"0.0.0", "0.0.9"}, []string{"0.1.0"}},
+		{"^0", []string{"0.0.0", "0.9.9"}, []string{"1.0.0"}},
+		{"^1.2.3-beta.2", []string{"1.2.3-beta.2", "1.2.3-beta.4", "1.2.3", "1.9.0"}, []string{"1.2.4-beta.2", "1.2.3-beta.1"}},
+		{"~0.1.33", []string{"0.1.33", "0.1.99"}, []string{"0.1.32", "0.2.0"}},
+		{"~1.2", []string{"1.2.0", "1.2.9"}, []string{"1.3.0"}},
+		{"~1", []string{"1.0.0", "1.9.0"}, []string{"2.0.0", "0.9.0"}},
+		{"1.x", []string{"1.0.0", "1.99.99"}, []string{"2.0.0", "0.9.0", "2.0.0-alpha"}},
+		{"1.2.*", []string{"1.2.0", "1.2.7"}, []string{"1.3.0"}},
+		{"1", []string{"1.0.0", "1.5.0"}, []string{"2.0.0"}},
+		{"*", []string{"0.0.0", "9.9.9"}, []string{"1.0.0-alpha"}},
+		{"x", []string{"1.0.0"}, []string{}},
+		{"1.2.3", []string{"1.2.3", "v1.2.3", "1.2.3+build"}, []string{"1.2.4"}},
+		{"=v1.2.3", []string{"1.2.3"}, []string{"1.2.4"}},
+		{">1.2", []string{"1.3.0"}, []string{"1.2.9"}},
+		{">1.2.3", []string{"1.2.4"}, []string{"1.2.3"}},
+		{"<1.2", []string{"1.1.9"}, []string{"1.2.0", "1.2.0-alpha"}},
+		{"<=1.2", []string{"1.2.9"}, []string{"1.3.0"}},
+		{"<=1.2.3", []string{"1.2.3"}, []string{"1.2.4"}},
+		{">*", []string{}, []string{"0.0.0", "1.0.0"}},
+		{"<*", []string{}, []string{"0.0.0"}},
+		{">=*", []string{"0.0.0"}, []string{}},
+		{"1.2.3 - 2.3.4", []string{"1.2.3", "2.3.4"}, []string{"2.3.5", "1.2.2"}},
+		{"1.2 - 2.3", []string{"1.2.0", "2.3.9"}, []string{"2.4.0", "1.1.9"}},
+		{"1 - 2", []string{"1.0.0", "2.9.9"}, []string{"3.0.0"}},
+		{"* - 2", []string{"0.0.0", "2.9.9"}, []string{"3.0.0"}},
+		{"1.2 - 1.4 || >=2.1", []string{"1.3.0", "2.1.0", "5.0.0"}, []string{"1.5.0", "2.0.0"}},
+		{"<1.0.0 || >=2.0.0-beta <2.0.0", []string{"0.5.0", "2.0.0-beta.1"}, []string{"1.0.0", "2.0.0"}},
+		{">=1.3.0-beta", []string{"1.3.0-rc.1", "1.3.0", "2.0.0"}, []string{"1.4.0-rc.1", "1.3.0-alpha"}},
+	}
+	for _, c := range cases {
+		for _, v := range c.matching {
+			ok, err := Satisfies(v, c.constraint)
+			assert.Nil(err)
+			assert.True(ok, v+" should satisfy "+c.constraint)
+		}
+		for _, v := range c.other {
+			ok, err := Satisfies(v, c.constraint)
+			assert.Nil(err)
+			assert.False(ok, v+" should not satisfy "+c.constraint)
+		}
+	}
+}
+
+func TestParseConstraintErrors(t *testing.T) {
+	assert := assert.New(t)
+	invalid := map[string]string{
+		"":          "constraint is empty. Use '*' to allow any version",
+		"1.2 ||":    "empty range around '||'",
+		">=":        "missing version after '>='",
+		">= <2":     "missing version after '>='",
+		">=abc":     "invalid version in '>=abc', non numeric value 'abc'",
+		"^1.2.3.4":  "invalid version in '^1.2.3.4', expected format major[.minor[.patch]]",
+		"1.x.3":     "invalid version in '1.x.3', number '3' after wildcard",
+		"01.2":      "invalid version in '01.2', leading zero in '01'",
+		"1.2.3-01":  "invalid version in '1.2.3-01', leading zero in pre-release identifier '01'",
+		"1 - 2 - 3": "hyphen range has to be of format '<version> - <version>'",
+		"1.2 - abc": "invalid version in 'abc', non numeric value 'abc'",
+		"abc - 1.2": "invalid version in 'abc', non numeric value 'abc'",
+		"~":         "missing version after '~'",
+		">=v":       "invalid version in '>=v', missing version",
+	}
+	for s, msg := range invalid {
+		_, err := ParseConstraint(s)
+		assert.ErrorIs(err, ErrInvalidConstraint, s)
+		assert.ErrorContains(err, "invalid version constraint '"+s+"', "+msg)
+	}
+
+	_, err := Satisfies("1.2", "1.x")
+	assert.ErrorIs(err, ErrInvalidVersion)
+	_, err = Satisfies("1.2.0", "1.x ||")
+	assert.ErrorIs(err, ErrInvalidConstraint)
+	assert.Panics(func() {
+		MustParseConstraint("")
+	})
+	assert.Equal("^1.2", MustParseConstraint("^1.2").String())
+}
+
+func TestMaxSatisfying(t *testing.T) {
+	assert := assert.New(t)
+	versions := []string{"1.2.3", "1.4.0", "v1.9.1", "2.0.0-rc.1", "2.0.0", "0.9.0"}
+	max, err := MaxSatisfying(versions, "^1.2")
+	assert.Nil(err)
+	assert.Equal("v1.9.1", max)
+
+	max, err = MaxSatisfying(versions, ">=2.0.0-rc.0")
+	assert.Nil(err)
+	assert.Equal("2.0.0", max)
+
+	max, err = MaxSatisfying(versions, "~1.5")
+	assert.Nil(err)
+	assert.Equal("", max)
+
+	_, err = MaxSatisfying(versions, "")
+	assert.ErrorIs(err, ErrInvalidConstraint)
+	_, err = MaxSatisfying([]string{"1.0.0", "latest"}, "*")
+	assert.ErrorIs(err, ErrInvalidVersion)
+}
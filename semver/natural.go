@@ -0,0 +1,117 @@
+package semver
+
+import (
+	"strings"
+)
+
+// Ordering strategy for version strings. Compare returns -1 if v1 < v2, 0 if v1 == v2 and 1 if v1 > v2
+type Comparator interface {
+	Compare(v1 string, v2 string) int
+}
+
+// Adapts a compare function to Comparator
+type ComparatorFunc func(v1 string, v2 string) int
+
+func (f ComparatorFunc) Compare(v1 string, v2 string) int {
+	return f(v1, v2)
+}
+
+// Orders versions in natural order with CompareNatural, e.g. 3-9 < 3-10 < 3-10a < 3-10b
+var NaturalComparator Comparator = ComparatorFunc(CompareNatural)
+
+// Orders semantic versions by precedence with Version.Compare, e.g. 1.0.0-rc.1 < 1.0.0 < 1.10.0.
+// Versions, which are not valid semantic versions, are ordered after valid ones, in natural order
+var SemanticComparator Comparator = ComparatorFunc(func(v1 string, v2 string) int {
+	s1, err1 := Parse(v1)
+	s2, err2 := Parse(v2)
+	switch {
+	case err1 == nil && err2 == nil:
+		return s1.Compare(s2)
+	case err1 == nil:
+		return -1
+	case err2 == nil:
+		return 1
+	default:
+		return CompareNatural(v1, v2)
+	}
+})
+
+// Orders versions with CompareSemver, splitting them by separator
+func SeparatorComparator(separator string) Comparator {
+	return ComparatorFunc(func(v1 string, v2 string) int {
+		le := CompareSemver(v1, v2, separator)
+		ge := CompareSemver(v2, v1, separator)
+		switch {
+		case le == ge:
+			// Equal parts, e.g. 1.01 & 1.1
+			return 0
+		case le:
+			return -1
+		default:
+			return 1
+		}
+	})
+}
+
+// Compares versions in natural order. Versions are split into runs of digits and runs of other characters,
+// e.g. 3-10a is [3, -, 10, a]. Digit runs are compared by numeric value, without overflow for long runs,
+// other runs are compared as strings, and a digit run is less than any other run.
+// A version, which is a prefix of another is less, e.g. 1.2 < 1.2.1 < 1.2.1a.
+// Returns -1 if v1 < v2, 0 if v1 == v2 and 1 if v1 > v2
+func CompareNatural(v1 string, v2 string) int {
+	runs1 := splitRuns(v1)
+	runs2 := splitRuns(v2)
+	for i := range min(len(runs1), len(runs2)) {
+		if c := compareRun(runs1[i], runs2[i]); c != 0 {
+			return c
+		}
+	}
+	if c := compareUint(uint64(len(runs1)), uint64(len(runs2))); c != 0 {
+		return c
+	}
+	// Same numeric values with different leading zeros, e.g. 1.01 and 1.1
+	return strings.Compare(v1, v2)
+}
+
+func splitRuns(v string) []string {
+	runs := []string{}
+	start := 0
+	for i, r := range v {
+		if i > start && isDigit(r) != isDigitRun(v[start:]) {
+			runs = append(runs, v[start:i])
+			start = i
+		}
+	}
+	if start < len(v) {
+		runs = append(runs, v[start:])
+	}
+	return runs
+}
+
+func isDigit(r rune) bool {
+	return r >= '0' && r <= '9'
+}
+
+func isDigitRun(run string) bool {
+	return run != "" && isDigit(rune(run[0]))
+}
+
+func compareRun(run1 string, run2 string) int {
+	digits1 := isDigitRun(run1)
+	digits2 := isDigitRun(run2)
+	switch {
+	case digits1 && digits2:
+		n1 := strings.TrimLeft(run1, "0")
+		n2 := strings.TrimLeft(run2, "0")
+		if c := compareUint(uint64(len(n1)), uint64(len(n2))); c != 0 {
+			return c
+		}
+		return strings.Compare(n1, n2)
+	case digits1:
+		return -1
+	case digits2:
+		return 1
+	default:
+		return strings.Compare(run1, run2)
+	}
+}
@@ -0,0 +1,76 @@
+package semver
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompareNatural(t *testing.T) {
+	assert := assert.New(t)
+	verArr := []string{
+		"3-10a",
+		"10",
+		"3-9",
+		"2024-01-15",
+		"3-10",
+		"9",
+		"3-10b",
+		"2024-1-2",
+		"1.2.1",
+		"1.2",
+		"1.2.a",
+		"99999999999999999999999",
+		"3-010",
+	}
+	slices.SortFunc(verArr, CompareNatural)
+
+	sortedVerArr := []string{
+		"1.2",
+		"1.2.1",
+		"1.2.a",
+		"3-9",
+		"3-010",
+		"3-10",
+		"3-10a",
+		"3-10b",
+		"9",
+		"10",
+		"2024-1-2",
+		"2024-01-15",
+		"99999999999999999999999",
+	}
+	assert.Equal(sortedVerArr, verArr)
+
+	assert.Equal(0, CompareNatural("", ""))
+	assert.Equal(-1, CompareNatural("", "1"))
+	assert.Equal(1, CompareNatural("a", "1"))
+	assert.Equal(0, CompareNatural("2-3", "2-3"))
+}
+
+func TestComparators(t *testing.T) {
+	assert := assert.New(t)
+	assert.Equal(-1, NaturalComparator.Compare("3-9", "3-10"))
+
+	cmp := SeparatorComparator("-")
+	assert.Equal(-1, cmp.Compare("3-9", "3-10"))
+	assert.Equal(0, cmp.Compare("3-10", "3-10"))
+	assert.Equal(1, cmp.Compare("3-10a", "3-10"))
+	assert.Equal(1, cmp.Compare("4", "3-10"))
+	assert.Equal(-1, cmp.Compare("3-10", "4"))
+	assert.Equal(0, cmp.Compare("1-01", "1-1"))
+	assert.Equal(0, cmp.Compare("1-1", "1-01"))
+
+	reversed := ComparatorFunc(func(v1 string, v2 string) int {
+		return CompareNatural(v2, v1)
+	})
+	assert.Equal(1, reversed.Compare("1", "2"))
+}
+
+func TestSemanticComparator(t *testing.T) {
+	assert := assert.New(t)
+	verArr := []string{"1.10.0", "not-semver", "1.0.0", "v1.2.0", "1.0.0-rc.1", "1.0"}
+	slices.SortFunc(verArr, SemanticComparator.Compare)
+	assert.Equal([]string{"1.0.0-rc.1", "1.0.0", "v1.2.0", "1.10.0", "1.0", "not-semver"}, verArr)
+}
@@ -15,7 +15,16 @@ func CompareSemver(v1 string, v2 string, separator string) bool {
 		v1Part, err1 := getVerPart(&v1Parts, i)
 		v2Part, err2 := getVerPart(&v2Parts, i)
 		if err1 != nil || err2 != nil {
-			return v1Parts[i] < v2Parts[i]
+			// Missing part is less than any part, e.g. 1 < 1.alpha
+			if i >= len(v1Parts) {
+				return true
+			}
+			if i >= len(v2Parts) {
+				return false
+			}
+			if v1Parts[i] != v2Parts[i] {
+				return v1Parts[i] < v2Parts[i]
+			}
 		} else {
 			if v1Part != v2Part {
 				return v1Part < v2Part
@@ -72,3 +72,12 @@ func TestBadData(t *testing.T) {
 
 	assert.Equal(sortedVerArr, verArr)
 }
+
+func TestUnevenLengths(t *testing.T) {
+	assert := assert.New(t)
+	assert.True(CompareSemver("1", "1.alpha", "."))
+	assert.False(CompareSemver("1.alpha", "1", "."))
+	assert.True(CompareSemver("1.alpha", "1.alpha", "."))
+	assert.True(CompareSemver("1.alpha", "1.alpha.1", "."))
+	assert.False(CompareSemver("1.beta.1", "1.alpha", "."))
+}
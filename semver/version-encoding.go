@@ -0,0 +1,62 @@
+package semver
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+)
+
+// Encodes version as its string, e.g. "1.2.3-rc.1"
+func (v Version) MarshalText() ([]byte, error) {
+	return []byte(v.String()), nil
+}
+
+// Decodes a version string, failing if it is not a valid semantic version
+func (v *Version) UnmarshalText(b []byte) error {
+	parsed, err := Parse(string(b))
+	if err != nil {
+		return err
+	}
+	*v = parsed
+	return nil
+}
+
+func (v Version) MarshalJSON() ([]byte, error) {
+	return json.Marshal(v.String())
+}
+
+// Decodes a JSON string with a valid semantic version. JSON null leaves v unchanged
+func (v *Version) UnmarshalJSON(b []byte) error {
+	if string(b) == "null" {
+		return nil
+	}
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return fmt.Errorf("%w %s, expected JSON string", ErrInvalidVersion, b)
+	}
+	return v.UnmarshalText([]byte(s))
+}
+
+// Decodes query and form params in gin binding
+func (v *Version) UnmarshalParam(param string) error {
+	return v.UnmarshalText([]byte(param))
+}
+
+// Scans a string column with a valid semantic version. Use *Version for nullable columns
+func (v *Version) Scan(src any) error {
+	switch value := src.(type) {
+	case string:
+		return v.UnmarshalText([]byte(value))
+	case []byte:
+		return v.UnmarshalText(value)
+	case nil:
+		return fmt.Errorf("%w, cannot scan NULL into Version", ErrInvalidVersion)
+	default:
+		return fmt.Errorf("%w, cannot scan %T into Version", ErrInvalidVersion, src)
+	}
+}
+
+// Stores version as its string
+func (v Version) Value() (driver.Value, error) {
+	return v.String(), nil
+}
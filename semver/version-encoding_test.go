@@ -0,0 +1,86 @@
+package semver
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/jmoiron/sqlx"
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+)
+
+type release struct {
+	Name    string   `json:"name" db:"name"`
+	Version Version  `json:"version" db:"version"`
+	Next    *Version `json:"next,omitempty" db:"next"`
+}
+
+func TestVersionJson(t *testing.T) {
+	assert := assert.New(t)
+	b, err := json.Marshal(release{Name: "a", Version: MustParse("1.2.3-rc.1+b.5")})
+	assert.Nil(err)
+	assert.Equal(`{"name":"a","version":"1.2.3-rc.1+b.5"}`, string(b))
+
+	r := release{}
+	assert.Nil(json.Unmarshal([]byte(`{"name":"a","version":"v1.2.3","next":"2.0.0"}`), &r))
+	assert.Equal(MustParse("1.2.3"), r.Version)
+	assert.Equal(MustParse("2.0.0"), *r.Next)
+
+	r = release{Version: MustParse("1.0.0")}
+	assert.Nil(json.Unmarshal([]byte(`{"version":null,"next":null}`), &r))
+	assert.Equal(MustParse("1.0.0"), r.Version)
+	assert.Nil(r.Next)
+
+	err = json.Unmarshal([]byte(`{"version":"1.2"}`), &r)
+	assert.ErrorIs(err, ErrInvalidVersion)
+	assert.ErrorContains(err, "invalid semantic version '1.2', expected format major.minor.patch")
+	err = json.Unmarshal([]byte(`{"version":123}`), &r)
+	assert.ErrorContains(err, "invalid semantic version 123, expected JSON string")
+}
+
+func TestVersionText(t *testing.T) {
+	assert := assert.New(t)
+	b, err := MustParse("0.1.33").MarshalText()
+	assert.Nil(err)
+	assert.Equal("0.1.33", string(b))
+
+	v := Version{}
+	assert.Nil(v.UnmarshalText([]byte("1.0.0-alpha")))
+	assert.Equal("1.0.0-alpha", v.String())
+	assert.ErrorIs(v.UnmarshalText([]byte("01.0.0")), ErrInvalidVersion)
+	assert.Equal("1.0.0-alpha", v.String())
+
+	assert.Nil(v.UnmarshalParam("3.4.5"))
+	assert.Equal("3.4.5", v.String())
+	assert.ErrorIs(v.UnmarshalParam("latest"), ErrInvalidVersion)
+
+}
+
+func TestVersionSql(t *testing.T) {
+	assert := assert.New(t)
+	db := sqlx.MustConnect("sqlite3", ":memory:")
+	defer db.Close()
+	db.MustExec("CREATE TABLE releases(name TEXT, version TEXT, next TEXT)")
+
+	_, err := db.NamedExec("INSERT INTO releases VALUES(:name, :version, :next)", release{Name: "a", Version: MustParse("1.2.3")})
+	assert.Nil(err)
+	next := MustParse("1.3.0-rc.1")
+	_, err = db.NamedExec("INSERT INTO releases VALUES(:name, :version, :next)", release{Name: "b", Version: MustParse("1.2.4"), Next: &next})
+	assert.Nil(err)
+
+	releases := []release{}
+	assert.Nil(db.Select(&releases, "SELECT name, version, next FROM releases ORDER BY name"))
+	assert.Equal([]release{{"a", MustParse("1.2.3"), nil}, {"b", MustParse("1.2.4"), &next}}, releases)
+
+	db.MustExec("INSERT INTO releases VALUES('c', '1.2', NULL)")
+	err = db.Select(&releases, "SELECT name, version, next FROM releases WHERE name = 'c'")
+	assert.ErrorIs(err, ErrInvalidVersion)
+	err = db.Select(&releases, "SELECT name, next AS version FROM releases WHERE name = 'a'")
+	assert.ErrorContains(err, "cannot scan NULL into Version")
+
+	v := Version{}
+	assert.ErrorContains(v.Scan(12), "cannot scan int into Version")
+	value, err := MustParse("1.0.0").Value()
+	assert.Nil(err)
+	assert.Equal("1.0.0", value)
+}
@@ -0,0 +1,196 @@
+package semver
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+var ErrInvalidVersion = errors.New("invalid semantic version")
+
+// Semantic version as per https://semver.org/spec/v2.0.0.html
+type Version struct {
+	Major      uint64
+	Minor      uint64
+	Patch      uint64
+	Prerelease []string
+	Build      []string
+}
+
+// Parses a version of format major.minor.patch[-prerelease][+build], e.g. 1.0.0-alpha.1+exp.sha.5114f85.
+// A leading 'v' is allowed, e.g. v1.2.3
+func Parse(s string) (Version, error) {
+	v, err := parse(s)
+	if err != nil {
+		return Version{}, fmt.Errorf("%w '%v', %w", ErrInvalidVersion, s, err)
+	}
+	return v, nil
+}
+
+func MustParse(s string) Version {
+	v, err := Parse(s)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+func parse(s string) (Version, error) {
+	v := Version{}
+	rest := strings.TrimPrefix(s, "v")
+	rest, build, hasBuild := strings.Cut(rest, "+")
+	if hasBuild {
+		ids, err := parseIdentifiers(build, "build metadata", false)
+		if err != nil {
+			return Version{}, err
+		}
+		v.Build = ids
+	}
+	core, prerelease, hasPrerelease := strings.Cut(rest, "-")
+	if hasPrerelease {
+		ids, err := parseIdentifiers(prerelease, "pre-release", true)
+		if err != nil {
+			return Version{}, err
+		}
+		v.Prerelease = ids
+	}
+	parts := strings.Split(core, ".")
+	if len(parts) != 3 {
+		return Version{}, errors.New("expected format major.minor.patch")
+	}
+	nums := [3]uint64{}
+	for i, part := range parts {
+		n, err := parseNumber(part)
+		if err != nil {
+			return Version{}, fmt.Errorf("%v in %v", err, []string{"major", "minor", "patch"}[i])
+		}
+		nums[i] = n
+	}
+	v.Major, v.Minor, v.Patch = nums[0], nums[1], nums[2]
+	return v, nil
+}
+
+func parseNumber(s string) (uint64, error) {
+	if s == "" {
+		return 0, errors.New("empty number")
+	}
+	if !isNumeric(s) {
+		return 0, fmt.Errorf("non numeric value '%v'", s)
+	}
+	if len(s) > 1 && s[0] == '0' {
+		return 0, fmt.Errorf("leading zero in '%v'", s)
+	}
+	n, err := strconv.ParseUint(s, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("number '%v' out of range", s)
+	}
+	return n, nil
+}
+
+// Identifiers are dot separated, non empty and contain only [0-9A-Za-z-].
+// Numeric pre-release identifiers must not have leading zeros
+func parseIdentifiers(s string, kind string, noLeadingZero bool) ([]string, error) {
+	ids := strings.Split(s, ".")
+	for _, id := range ids {
+		if id == "" {
+			return nil, fmt.Errorf("empty %v identifier", kind)
+		}
+		for _, c := range id {
+			if !isIdentifierRune(c) {
+				return nil, fmt.Errorf("invalid character '%c' in %v identifier '%v'", c, kind, id)
+			}
+		}
+		if noLeadingZero && isNumeric(id) && len(id) > 1 && id[0] == '0' {
+			return nil, fmt.Errorf("leading zero in %v identifier '%v'", kind, id)
+		}
+	}
+	return ids, nil
+}
+
+func isIdentifierRune(c rune) bool {
+	return (c >= '0' && c <= '9') || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || c == '-'
+}
+
+func isNumeric(s string) bool {
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return s != ""
+}
+
+func (v Version) String() string {
+	sb := strings.Builder{}
+	fmt.Fprintf(&sb, "%d.%d.%d", v.Major, v.Minor, v.Patch)
+	if len(v.Prerelease) > 0 {
+		sb.WriteString("-" + strings.Join(v.Prerelease, "."))
+	}
+	if len(v.Build) > 0 {
+		sb.WriteString("+" + strings.Join(v.Build, "."))
+	}
+	return sb.String()
+}
+
+// Returns -1, 0 or 1 if v has lower, equal or higher precedence than o. Build metadata is ignored
+func (v Version) Compare(o Version) int {
+	if c := compareUint(v.Major, o.Major); c != 0 {
+		return c
+	}
+	if c := compareUint(v.Minor, o.Minor); c != 0 {
+		return c
+	}
+	if c := compareUint(v.Patch, o.Patch); c != 0 {
+		return c
+	}
+	// A pre-release version has lower precedence than the normal version
+	switch {
+	case len(v.Prerelease) == 0 && len(o.Prerelease) == 0:
+		return 0
+	case len(v.Prerelease) == 0:
+		return 1
+	case len(o.Prerelease) == 0:
+		return -1
+	}
+	for i := 0; i < len(v.Prerelease) && i < len(o.Prerelease); i++ {
+		if c := compareIdentifier(v.Prerelease[i], o.Prerelease[i]); c != 0 {
+			return c
+		}
+	}
+	return compareUint(uint64(len(v.Prerelease)), uint64(len(o.Prerelease)))
+}
+
+func (v Version) IsPrerelease() bool {
+	return len(v.Prerelease) > 0
+}
+
+// Numeric identifiers are compared numerically and have lower precedence than alphanumeric ones,
+// which are compared in ASCII sort order
+func compareIdentifier(a string, b string) int {
+	aNum, bNum := isNumeric(a), isNumeric(b)
+	switch {
+	case aNum && bNum:
+		if c := compareUint(uint64(len(a)), uint64(len(b))); c != 0 {
+			return c
+		}
+		return strings.Compare(a, b)
+	case aNum:
+		return -1
+	case bNum:
+		return 1
+	default:
+		return strings.Compare(a, b)
+	}
+}
+
+func compareUint(a uint64, b uint64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
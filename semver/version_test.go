@@ -0,0 +1,100 @@
+package semver
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	assert := assert.New(t)
+	v, err := Parse("1.0.0-alpha.1+exp.sha.5114f85")
+	assert.Nil(err)
+	assert.Equal(Version{Major: 1, Prerelease: []string{"alpha", "1"}, Build: []string{"exp", "sha", "5114f85"}}, v)
+	assert.Equal("1.0.0-alpha.1+exp.sha.5114f85", v.String())
+	assert.True(v.IsPrerelease())
+
+	v, err = Parse("v10.20.30")
+	assert.Nil(err)
+	assert.Equal("10.20.30", v.String())
+	assert.False(v.IsPrerelease())
+
+	valid := []string{
+		"0.0.4", "1.2.3", "1.1.2-prerelease+meta", "1.1.2+meta", "1.1.2+meta-valid", "1.0.0-alpha",
+		"1.0.0-alpha.beta", "1.0.0-alpha.0valid", "1.0.0-rc.1+build.1", "2.0.0-rc.1+build.123",
+		"10.2.3-DEV-SNAPSHOT", "1.2.3-SNAPSHOT-123", "1.0.0+0.build.1-rc.10000aaa-kk-0.1",
+		"99999999999999999.999999999999999999.99999999999999999", "1.0.0-0A.is.legal", "1.2.3----RC-SNAPSHOT.12.9.1--.12+788",
+	}
+	for _, s := range valid {
+		v, err := Parse(s)
+		assert.Nil(err, s)
+		assert.Equal(s, v.String())
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	assert := assert.New(t)
+	invalid := map[string]string{
+		"1":                        "expected format major.minor.patch",
+		"1.2":                      "expected format major.minor.patch",
+		"1.2.3.4":                  "expected format major.minor.patch",
+		"01.1.1":                   "leading zero in '01' in major",
+		"1.01.1":                   "leading zero in '01' in minor",
+		"1.1.a":                    "non numeric value 'a' in patch",
+		"1..1":                     "empty number in minor",
+		"1.2.3-0123":               "leading zero in pre-release identifier '0123'",
+		"1.2.3-alpha..1":           "empty pre-release identifier",
+		"1.2.3-":                   "empty pre-release identifier",
+		"1.2.3+":                   "empty build metadata identifier",
+		"1.2.3-alpha_beta":         "invalid character '_' in pre-release identifier 'alpha_beta'",
+		"1.2.3+build+meta":         "invalid character '+' in build metadata identifier 'build+meta'",
+		"99999999999999999999.0.0": "number '99999999999999999999' out of range",
+	}
+	for s, msg := range invalid {
+		_, err := Parse(s)
+		assert.ErrorIs(err, ErrInvalidVersion, s)
+		assert.ErrorContains(err, "invalid semantic version '"+s+"', "+msg)
+	}
+	assert.Panics(func() {
+		MustParse("1.2")
+	})
+	assert.Equal("1.2.3", MustParse("1.2.3").String())
+}
+
+func TestComparePrecedence(t *testing.T) {
+	assert := assert.New(t)
+	// Examples from the spec, in increasing precedence
+	ordered := []string{
+		"1.0.0-alpha", "1.0.0-alpha.1", "1.0.0-alpha.beta", "1.0.0-beta", "1.0.0-beta.2",
+		"1.0.0-beta.11", "1.0.0-rc.1", "1.0.0", "2.0.0", "2.1.0", "2.1.1",
+	}
+	for i := range ordered {
+		for j := range ordered {
+			expected := 0
+			if i < j {
+				expected = -1
+			} else if i > j {
+				expected = 1
+			}
+			assert.Equal(expected, MustParse(ordered[i]).Compare(MustParse(ordered[j])), ordered[i]+" vs "+ordered[j])
+		}
+	}
+
+	shuffled := slices.Clone(ordered)
+	slices.Reverse(shuffled)
+	versions := []Version{}
+	for _, s := range shuffled {
+		versions = append(versions, MustParse(s))
+	}
+	slices.SortFunc(versions, Version.Compare)
+	for i, v := range versions {
+		assert.Equal(ordered[i], v.String())
+	}
+
+	// Build metadata does not affect precedence
+	assert.Equal(0, MustParse("1.0.0+20130313144700").Compare(MustParse("1.0.0+exp.sha.5114f85")))
+	assert.Equal(0, MustParse("v1.0.0").Compare(MustParse("1.0.0")))
+	assert.Equal(-1, MustParse("1.0.0-9").Compare(MustParse("1.0.0-10")))
+	assert.Equal(-1, MustParse("1.0.0-10").Compare(MustParse("1.0.0-a")))
+}